@@ -2,31 +2,47 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/zekrotja/rogu/log"
 
 	"github.com/joho/godotenv"
 	"github.com/ranna-go/ranna/internal/api"
+	"github.com/ranna-go/ranna/internal/auth"
 	"github.com/ranna-go/ranna/internal/config"
 	"github.com/ranna-go/ranna/internal/file"
+	"github.com/ranna-go/ranna/internal/grpcapi"
+	"github.com/ranna-go/ranna/internal/health"
+	"github.com/ranna-go/ranna/internal/history"
+	"github.com/ranna-go/ranna/internal/jobs"
+	"github.com/ranna-go/ranna/internal/metrics"
 	"github.com/ranna-go/ranna/internal/namespace"
+	"github.com/ranna-go/ranna/internal/ratelimit"
 	"github.com/ranna-go/ranna/internal/sandbox"
 	"github.com/ranna-go/ranna/internal/sandbox/docker"
 	"github.com/ranna-go/ranna/internal/scheduler"
+	"github.com/ranna-go/ranna/internal/snippets"
 	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/internal/tracing"
+	"github.com/ranna-go/ranna/internal/webhooks"
 )
 
+// webhookShutdownTimeout is the maximum duration to wait
+// for pending callback deliveries on shutdown.
+const webhookShutdownTimeout = 15 * time.Second
+
 type ConfigProvider interface {
 	Config() *config.Config
 }
 
 type Scheduler interface {
-	Schedule(spec any, job func()) (id any, err error)
+	Schedule(name string, spec any, job func() error) (id any, err error)
 }
 
 type Manager interface {
@@ -38,6 +54,10 @@ type SpecProvider interface {
 	Load() error
 }
 
+type HistoryStore interface {
+	DeleteBefore(t time.Time) (n int, err error)
+}
+
 func checkErr(err error) {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "initialization failed: %v\n", err)
@@ -57,6 +77,10 @@ func main() {
 
 	log.SetLevel(cfg.Config().Log.Level)
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
+	checkErr(err)
+	defer shutdownTracing(context.Background())
+
 	if cfg.Config().Sandbox.EnableNetworking {
 		log.Warn().Msg("ATTENTION: Sandbox Networking is enabled by config! This is a high security risk!")
 	}
@@ -78,6 +102,18 @@ func main() {
 
 	namespaceProvider := namespace.NewRandomProvider()
 
+	// Deferred before the sandbox cleanup so that the
+	// callbacks of killed executions are awaited as well.
+	webhookDispatcher := webhooks.NewDispatcher(cfg)
+	defer func() {
+		log.Info().Msg("waiting for pending callback deliveries ...")
+		waitCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		if err := webhookDispatcher.Wait(waitCtx); err != nil {
+			log.Warn().Err(err).Msg("Pending callback deliveries have been aborted")
+		}
+	}()
+
 	sandboxManager, err := sandbox.NewManager(sandboxProvider, specProvider, fileProvider, cfg, namespaceProvider)
 	checkErr(err)
 	defer func() {
@@ -86,33 +122,129 @@ func main() {
 		sandboxManager.Cleanup(ctx)
 	}()
 
-	webApi, err := api.NewRestAPI(cfg, specProvider, sandboxManager)
+	var executor api.SandboxManager = sandboxManager
+	var historyStore history.Store
+	if cfg.Config().History.Enabled {
+		switch cfg.Config().History.Store {
+		case "memory":
+			historyStore = history.NewMemoryStore()
+		case "bolt":
+			historyStore, err = history.NewBoltStore(cfg.Config().History.Path)
+			checkErr(err)
+		default:
+			checkErr(fmt.Errorf("invalid history store: %s", cfg.Config().History.Store))
+		}
+		defer historyStore.Close()
+		executor = history.NewRecorder(sandboxManager, historyStore, cfg)
+	}
+
+	if cfg.Config().Webhooks.AllowedHosts != "" && cfg.Config().Webhooks.Secret == "" {
+		log.Warn().Msg("No webhook secret is configured, callback signatures can not be trusted")
+	}
+	executor = webhooks.NewNotifier(executor, webhookDispatcher, cfg)
+
+	// The usage recorder wraps all other managers so that
+	// executions rejected by any of them are refunded.
+	limiter, err := ratelimit.NewManager(cfg)
+	checkErr(err)
+	executor = ratelimit.NewUsageRecorder(executor, limiter)
+
+	jobManager := jobs.NewManager(executor, jobs.NewMemoryStore(), cfg)
+
+	var snippetStore snippets.Store
+	switch cfg.Config().Snippets.Store {
+	case "memory":
+		snippetStore = snippets.NewMemoryStore()
+	case "file":
+		snippetStore, err = snippets.NewFileStore(cfg.Config().Snippets.Path)
+		checkErr(err)
+	default:
+		checkErr(fmt.Errorf("invalid snippet store: %s", cfg.Config().Snippets.Store))
+	}
+	snippetManager := snippets.NewManager(snippetStore, specProvider, cfg)
+
+	authenticator, err := auth.NewAuthenticator(cfg)
 	checkErr(err)
+	if cfg.Config().Auth.KeysPath == "" {
+		if !cfg.Config().Auth.AllowUnauthenticated {
+			log.Warn().Msg("No API keys are configured and unauthenticated access is disabled, all requests will be rejected")
+		}
+	} else {
+		log.Info().Field("path", cfg.Config().Auth.KeysPath).Msg("API keys loaded")
+	}
+
+	healthChecker := health.NewChecker(cfg, specProvider, sandboxProvider)
 
 	schedulerProvider := scheduler.NewCronScheduler()
 	schedulerProvider.Start()
 	defer schedulerProvider.Stop()
 
+	webApi, err := api.NewRestAPI(cfg, specProvider, executor, jobManager, historyStore, snippetManager,
+		authenticator, limiter, sandboxManager, schedulerProvider, healthChecker)
+	checkErr(err)
+
+	var grpcApi *grpcapi.Server
+	if cfg.Config().API.GRPC.BindAddress != "" {
+		grpcApi, err = grpcapi.NewServer(cfg, specProvider, executor, authenticator, limiter)
+		checkErr(err)
+		defer grpcApi.Stop()
+	}
+
+	metrics.RegisterSandboxManager(sandboxManager)
+	if cfg.Config().Metrics.BindAddress != "" {
+		metricsServer := metrics.NewServer(cfg)
+		defer metricsServer.Shutdown(context.Background())
+		go func() {
+			err := metricsServer.ListenAndServeBlocking()
+			checkErr(err)
+		}()
+	}
+
+	// The REST API is started before the spec environments
+	// are prepared so that the readiness can be probed.
+	go func() {
+		err := webApi.ListenAndServeBlocking()
+		checkErr(err)
+	}()
+
 	if !cfg.Config().SkipStartupPrep {
 		log.Info().Msg("Prepare spec environments ...")
-		// TODO: Handle errors
-		sandboxManager.PrepareEnvironments(ctx, true)
+		errs := sandboxManager.PrepareEnvironments(ctx, true)
+		if len(errs) != 0 {
+			log.Warn().Field("n", len(errs)).Msg("Some spec environments failed to prepare")
+		}
+		healthChecker.SetPrepared(errs)
 	} else {
 		log.Warn().Msg("Skipping spec preparation on startup")
+		healthChecker.SetPrepared(nil)
 	}
 
-	if err := scheduleTasks(ctx, cfg, schedulerProvider, sandboxManager, specProvider); err != nil {
+	sandboxManager.StartPool(ctx)
+
+	if err := scheduleTasks(ctx, cfg, schedulerProvider, sandboxManager, specProvider, historyStore); err != nil {
 		log.Fatal().Err(err).Msg("failed scheduling job")
 	}
 
-	go func() {
-		err = webApi.ListenAndServeBlocking()
-		checkErr(err)
-	}()
+	if grpcApi != nil {
+		go func() {
+			err := grpcApi.ListenAndServeBlocking()
+			checkErr(err)
+		}()
+	}
 
+	// SIGHUP reloads the API keys without restarting.
 	sc := make(chan os.Signal, 1)
-	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-sc
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, os.Interrupt)
+	for sig := range sc {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if err := authenticator.Load(); err != nil {
+			log.Error().Err(err).Msg("Failed reloading API keys")
+		} else {
+			log.Info().Msg("API keys reloaded")
+		}
+	}
 }
 
 func scheduleTasks(
@@ -121,36 +253,58 @@ func scheduleTasks(
 	sched Scheduler,
 	mgr Manager,
 	specProvider SpecProvider,
+	historyStore HistoryStore,
 ) (err error) {
-	schedule := func(name, spec string, job func()) (err error) {
+	schedule := func(name, spec string, job func() error) (err error) {
 		if spec != "" {
 			log.Info().Field("name", name).Field("spec", spec).Msg("Scheduling job")
-			_, err = sched.Schedule(spec, job)
+			_, err = sched.Schedule(name, spec, job)
 		}
 		return err
 	}
 
 	scheduleSpec := cfg.Config().Scheduler.UpdateImages
-	err = schedule("update spec environments", scheduleSpec, func() {
+	err = schedule("update spec environments", scheduleSpec, func() error {
 		log.Info().Msg("Updating spec environments ...")
 		defer log.Info().Msg("Updating spec finished")
-		mgr.PrepareEnvironments(ctx, true)
+		return errors.Join(mgr.PrepareEnvironments(ctx, true)...)
 	})
 	if err != nil {
 		return err
 	}
 
 	scheduleSpec = cfg.Config().Scheduler.UpdateSpecs
-	err = schedule("update specs", scheduleSpec, func() {
-		if err = specProvider.Load(); err != nil {
+	err = schedule("update specs", scheduleSpec, func() error {
+		err := specProvider.Load()
+		metrics.SpecReloads.WithLabelValues(metrics.Result(err)).Inc()
+		if err != nil {
 			log.Error().Err(err).Msg("Failed loading specs")
 		} else {
 			log.Info().Msg("Specs updated")
 		}
+		return err
 	})
 	if err != nil {
 		return err
 	}
 
+	// A retention of 0 keeps the history forever.
+	if historyStore != nil && cfg.Config().History.RetentionHours > 0 {
+		scheduleSpec = cfg.Config().Scheduler.CleanupHistory
+		retention := time.Duration(cfg.Config().History.RetentionHours) * time.Hour
+		err = schedule("cleanup history", scheduleSpec, func() error {
+			n, err := historyStore.DeleteBefore(time.Now().Add(-retention))
+			if err != nil {
+				log.Error().Err(err).Msg("Failed cleaning up history")
+			} else {
+				log.Info().Field("n", n).Msg("History cleaned up")
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
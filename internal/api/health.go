@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// healthz reports that the process is alive.
+func healthz(ctx *fiber.Ctx) error {
+	return ctx.SendStatus(fiber.StatusOK)
+}
+
+// readyz runs the readiness checks and responds
+// with their results. If any check failed, the
+// status is 503 Service Unavailable.
+func readyz(health HealthChecker) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		res := health.Check(ctx.Context())
+		if !res.Ready {
+			ctx.Status(fiber.StatusServiceUnavailable)
+		}
+		return ctx.JSON(res)
+	}
+}
@@ -16,7 +16,19 @@ type RestAPI struct {
 	app         *fiber.App
 }
 
-func NewRestAPI(cfg ConfigProvider, spec SpecProvider, manager SandboxManager) (t *RestAPI, err error) {
+func NewRestAPI(
+	cfg ConfigProvider,
+	spec SpecProvider,
+	manager SandboxManager,
+	jobs JobManager,
+	history HistoryStore,
+	snippets SnippetManager,
+	authenticator Authenticator,
+	limiter RateLimiter,
+	admin AdminManager,
+	scheduler Scheduler,
+	health HealthChecker,
+) (t *RestAPI, err error) {
 
 	t = &RestAPI{
 		bindAddress: cfg.Config().API.BindAddress,
@@ -26,16 +38,31 @@ func NewRestAPI(cfg ConfigProvider, spec SpecProvider, manager SandboxManager) (
 	if tp := cfg.Config().API.TrustedProxies; tp != "" {
 		trustedProxies = strings.Split(tp, " ")
 	}
+	// The client address is only taken from the proxy
+	// header if trusted proxies are configured. Otherwise,
+	// clients could bypass the rate limits by spoofing it.
+	var proxyHeader string
+	if len(trustedProxies) != 0 {
+		proxyHeader = fiber.HeaderXForwardedFor
+	}
+
 	t.app = fiber.New(fiber.Config{
 		DisableStartupMessage:   !cfg.Config().Debug,
 		ServerHeader:            "ranna",
 		ErrorHandler:            errorHandler,
 		EnableTrustedProxyCheck: len(trustedProxies) != 0,
 		TrustedProxies:          trustedProxies,
-		ProxyHeader:             "X-Forwarded-For",
+		ProxyHeader:             proxyHeader,
 	})
 
-	new(v1.Router).Setup(t.app.Group("/v1"), cfg, spec, manager)
+	// The probes are registered before the middlewares
+	// so that they are neither traced nor rate limited.
+	t.app.Get("/healthz", healthz)
+	t.app.Get("/readyz", readyz(health))
+
+	t.app.Use(traceRequest)
+
+	new(v1.Router).Setup(t.app.Group("/v1"), cfg, spec, manager, jobs, history, snippets, authenticator, limiter, admin, scheduler)
 
 	return
 }
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ranna-go/ranna/internal/api")
+
+// headerCarrier passes the trace context
+// using the headers of a request.
+type headerCarrier struct {
+	ctx *fiber.Ctx
+}
+
+func (t headerCarrier) Get(key string) string {
+	return t.ctx.Get(key)
+}
+
+func (t headerCarrier) Set(key, value string) {
+	t.ctx.Request().Header.Set(key, value)
+}
+
+func (t headerCarrier) Keys() []string {
+	var keys []string
+	t.ctx.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// traceRequest starts a span for each request continuing
+// the trace context passed with the request headers. The
+// span is passed to the handlers with the user context.
+func traceRequest(ctx *fiber.Ctx) error {
+	parent := otel.GetTextMapPropagator().Extract(ctx.UserContext(), headerCarrier{ctx})
+	spanCtx, span := tracer.Start(parent, ctx.Method()+" "+ctx.Path(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(ctx.Method()),
+			semconv.URLPath(ctx.Path()),
+			semconv.ClientAddress(ctx.IP())))
+	defer span.End()
+
+	ctx.SetUserContext(spanCtx)
+	err := ctx.Next()
+
+	// The error is turned into a response by the error
+	// handler after the middleware has returned.
+	status := ctx.Response().StatusCode()
+	var fErr *fiber.Error
+	if errors.As(err, &fErr) {
+		status = fErr.Code
+	} else if err != nil {
+		status = fiber.StatusInternalServerError
+	}
+
+	route := ctx.Route().Path
+	span.SetName(ctx.Method() + " " + route)
+	span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, utils.StatusMessage(status))
+	}
+
+	return err
+}
@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zekrotja/rogu/log"
+
+	"github.com/ranna-go/ranna/internal/metrics"
+)
+
+var (
+	errSandboxNotFound = fiber.NewError(fiber.StatusNotFound, "sandbox not found")
+	errSpecNotFound    = fiber.NewError(fiber.StatusNotFound, "spec not found")
+)
+
+// @summary List Running Sandboxes
+// @description Returns the executions with a running sandbox ordered by their start time.
+// @produce json
+// @success 200 {array} models.RunningSandbox
+// @failure 403 {object} models.ErrorModel
+// @router /admin/sandboxes [get]
+func (t *Router) getAdminSandboxes(ctx *fiber.Ctx) (err error) {
+	return ctx.JSON(t.admin.RunningSandboxes())
+}
+
+// @summary Kill Sandbox
+// @description Kills the running sandbox of the execution with the given run ID.
+// @param id path string true "The run ID"
+// @success 204
+// @failure 403 {object} models.ErrorModel
+// @failure 404 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /admin/sandboxes/{id} [delete]
+func (t *Router) deleteAdminSandbox(ctx *fiber.Ctx) (err error) {
+	id := ctx.Params("id")
+	ok, err := t.admin.KillAndCleanUp(ctx.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errSandboxNotFound
+	}
+
+	log.Info().Fields("id", id, "admin", identity(ctx).Name).Msg("sandbox killed by admin")
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// @summary Prepare Environments
+// @description Prepares the environments of all specs. If force is set,
+// @description the images are pulled even if they are present and all
+// @description pooled sandboxes are re-created.
+// @param force query bool false "Pull images even if they are present"
+// @success 204
+// @failure 403 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /admin/prepare [post]
+func (t *Router) postAdminPrepare(ctx *fiber.Ctx) (err error) {
+	errs := t.admin.PrepareEnvironments(ctx.UserContext(), ctx.QueryBool("force"))
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// @summary Prepare Spec Environment
+// @description Prepares the environment of a single spec. If force is set,
+// @description the image is pulled even if it is present and the pooled
+// @description sandboxes of the spec are re-created.
+// @param language path string true "The spec name"
+// @param force query bool false "Pull the image even if it is present"
+// @success 204
+// @failure 403 {object} models.ErrorModel
+// @failure 404 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /admin/prepare/{language} [post]
+func (t *Router) postAdminPrepareSpec(ctx *fiber.Ctx) (err error) {
+	language := ctx.Params("language")
+	if _, _, ok := t.spec.Spec().Resolve(language); !ok {
+		return errSpecNotFound
+	}
+
+	if err = t.admin.PrepareEnvironment(ctx.UserContext(), language, ctx.QueryBool("force")); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// @summary Reload Specs
+// @description Reloads the spec map from the configured spec file
+// @description and returns the reloaded spec map.
+// @produce json
+// @success 200 {object} models.SpecMap
+// @failure 403 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /admin/specs/reload [post]
+func (t *Router) postAdminSpecsReload(ctx *fiber.Ctx) (err error) {
+	err = t.spec.Load()
+	metrics.SpecReloads.WithLabelValues(metrics.Result(err)).Inc()
+	if err != nil {
+		return err
+	}
+
+	log.Info().Field("admin", identity(ctx).Name).Msg("specs reloaded by admin")
+	return ctx.JSON(t.spec.Spec().GetSnapshot())
+}
+
+// @summary Get Scheduler Jobs
+// @description Returns the state of the scheduled background jobs.
+// @produce json
+// @success 200 {array} models.SchedulerJob
+// @failure 403 {object} models.ErrorModel
+// @router /admin/scheduler [get]
+func (t *Router) getAdminScheduler(ctx *fiber.Ctx) (err error) {
+	return ctx.JSON(t.scheduler.Jobs())
+}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// fakeAuthenticator grants the admin scope to
+// unauthenticated requests and to the "admin" key.
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(header, addr string) (*auth.Identity, error) {
+	id := &auth.Identity{Addr: addr, Scopes: []auth.Scope{auth.ScopeAdmin}}
+	if header != "" {
+		id.Name = header
+		if header != "admin" {
+			id.Scopes = []auth.Scope{auth.ScopeExec}
+		}
+	}
+	return id, nil
+}
+
+type fakeAdminManager struct {
+	AdminManager
+
+	running []models.RunningSandbox
+}
+
+func (t *fakeAdminManager) RunningSandboxes() []models.RunningSandbox {
+	return t.running
+}
+
+func (t *fakeAdminManager) KillAndCleanUp(_ context.Context, id string) (bool, error) {
+	for _, s := range t.running {
+		if s.RunId == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newAdminTestApp() *fiber.App {
+	r := &Router{
+		auth: fakeAuthenticator{},
+		admin: &fakeAdminManager{running: []models.RunningSandbox{
+			{RunId: "run", Language: "go", Client: "key:someone"},
+		}},
+	}
+
+	app := fiber.New()
+	app.Use(r.authenticate)
+	app.Get("/admin/sandboxes", r.requireAdmin, r.getAdminSandboxes)
+	app.Delete("/admin/sandboxes/:id", r.requireAdmin, r.deleteAdminSandbox)
+	return app
+}
+
+func TestRequireAdmin(t *testing.T) {
+	app := newAdminTestApp()
+
+	for header, expected := range map[string]int{
+		"":      fiber.StatusForbidden,
+		"user":  fiber.StatusForbidden,
+		"admin": fiber.StatusOK,
+	} {
+		req := httptest.NewRequest("GET", "/admin/sandboxes", nil)
+		req.Header.Set(fiber.HeaderAuthorization, header)
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != expected {
+			t.Errorf("invalid status for %q: %d (expected: %d)", header, res.StatusCode, expected)
+		}
+		if res.StatusCode != fiber.StatusOK {
+			continue
+		}
+
+		var sandboxes []models.RunningSandbox
+		if err = json.NewDecoder(res.Body).Decode(&sandboxes); err != nil {
+			t.Fatal(err)
+		}
+		if len(sandboxes) != 1 || sandboxes[0].RunId != "run" {
+			t.Errorf("invalid sandboxes: %+v", sandboxes)
+		}
+	}
+}
+
+func TestDeleteAdminSandbox(t *testing.T) {
+	app := newAdminTestApp()
+
+	for id, expected := range map[string]int{
+		"run":     fiber.StatusNoContent,
+		"unknown": fiber.StatusNotFound,
+	} {
+		req := httptest.NewRequest("DELETE", "/admin/sandboxes/"+id, nil)
+		req.Header.Set(fiber.HeaderAuthorization, "admin")
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != expected {
+			t.Errorf("invalid status for %q: %d (expected: %d)", id, res.StatusCode, expected)
+		}
+	}
+}
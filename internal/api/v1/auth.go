@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zekrotja/rogu/log"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// identityKey is the key of the locals the identity
+// of a request is stored under. It must be a string
+// so that it is passed to WebSocket connections.
+const identityKey = "identity"
+
+var (
+	errMissingScope       = fiber.NewError(fiber.StatusForbidden, "missing scope")
+	errLanguageNotAllowed = fiber.NewError(fiber.StatusForbidden, "language is not allowed")
+	errAdminRequired      = fiber.NewError(fiber.StatusForbidden, "admin credential required")
+)
+
+// authenticate authenticates the request by its
+// Authorization header and stores the identity
+// in the locals of the request.
+func (t *Router) authenticate(ctx *fiber.Ctx) error {
+	id, err := t.auth.Authenticate(ctx.Get(fiber.HeaderAuthorization), ctx.IP())
+	if err != nil {
+		log.Debug().Err(err).Field("addr", ctx.IP()).Field("path", ctx.Path()).Msg("authentication failed")
+		ctx.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+	ctx.Locals(identityKey, id)
+	return ctx.Next()
+}
+
+// requireScope returns a handler which rejects requests
+// whose identity is not granted the given scope.
+func (t *Router) requireScope(scope auth.Scope) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if !identity(ctx).HasScope(scope) {
+			return errMissingScope
+		}
+		return ctx.Next()
+	}
+}
+
+// requireAdmin rejects requests which are not
+// authenticated by an API key granting the admin
+// scope. Unauthenticated requests are rejected even
+// if the admin scope is granted to them by config.
+func (t *Router) requireAdmin(ctx *fiber.Ctx) error {
+	if id := identity(ctx); !id.Authenticated() || !id.HasScope(auth.ScopeAdmin) {
+		return errAdminRequired
+	}
+	return ctx.Next()
+}
+
+// checkLanguage returns an error if the identity of the
+// request is not allowed to execute the language of req.
+func (t *Router) checkLanguage(ctx *fiber.Ctx, req *models.ExecutionRequest) error {
+	if !identity(ctx).CanExec(t.spec.Spec(), req.Language) {
+		return errLanguageNotAllowed
+	}
+	return nil
+}
+
+// identity returns the identity of the request.
+func identity(ctx *fiber.Ctx) *auth.Identity {
+	id, _ := ctx.Locals(identityKey).(*auth.Identity)
+	return id
+}
+
+// withClient returns a copy of parent carrying the
+// identity of the request as client identity.
+func withClient(parent context.Context, ctx *fiber.Ctx) context.Context {
+	id := identity(ctx)
+	return sandbox.WithClient(auth.WithIdentity(parent, id), id.Client())
+}
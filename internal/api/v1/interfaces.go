@@ -3,7 +3,10 @@ package v1
 import (
 	"context"
 
+	"github.com/ranna-go/ranna/internal/auth"
 	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/history"
+	"github.com/ranna-go/ranna/internal/ratelimit"
 	"github.com/ranna-go/ranna/internal/sandbox"
 	"github.com/ranna-go/ranna/internal/spec"
 	"github.com/ranna-go/ranna/pkg/models"
@@ -11,6 +14,7 @@ import (
 
 type SpecProvider interface {
 	Spec() *spec.SafeSpecMap
+	Load() error
 }
 
 type ConfigProvider interface {
@@ -21,12 +25,50 @@ type SandboxManager interface {
 	RunInSandbox(
 		ctx context.Context,
 		req *models.ExecutionRequest,
-		cSpn chan string,
-		cOut chan []byte,
-		cErr chan []byte,
-	) (err error)
+		chans sandbox.RunChannels,
+	) (res *sandbox.RunResult, err error)
 	PrepareEnvironments(ctx context.Context, force bool) []error
 	KillAndCleanUp(ctx context.Context, id string) (bool, error)
+	WriteStdin(id string, p []byte) (bool, error)
+	CloseStdin(id string) (bool, error)
 	Cleanup(ctx context.Context) []error
 	GetProvider() sandbox.Provider
+	PoolInfo() map[string]models.PoolInfo
+}
+
+type JobManager interface {
+	Create(ctx context.Context, req *models.ExecutionRequest) (*models.Job, error)
+	Get(id string) (*models.Job, error)
+	Cancel(ctx context.Context, id string) (*models.Job, error)
+}
+
+type HistoryStore interface {
+	Get(id string) (e *models.Execution, ok bool, err error)
+	List(filter history.Filter) ([]*models.Execution, error)
+}
+
+type SnippetManager interface {
+	Create(req *models.SnippetRequest) (*models.Snippet, error)
+	Get(id string) (*models.Snippet, error)
+}
+
+type Authenticator interface {
+	Authenticate(header, addr string) (*auth.Identity, error)
+}
+
+type RateLimiter interface {
+	Allow(id *auth.Identity, name string) ratelimit.Result
+	ReserveExecution(id *auth.Identity) ratelimit.Result
+	RefundExecution(client string)
+}
+
+type AdminManager interface {
+	RunningSandboxes() []models.RunningSandbox
+	KillAndCleanUp(ctx context.Context, id string) (bool, error)
+	PrepareEnvironments(ctx context.Context, force bool) []error
+	PrepareEnvironment(ctx context.Context, language string, force bool) error
+}
+
+type Scheduler interface {
+	Jobs() []models.SchedulerJob
 }
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/ranna-go/ranna/internal/ratelimit"
+)
+
+// rateLimit returns a handler which takes requests from
+// the rate limit with the given name and passes the state
+// of the limit with the X-RateLimit-* headers.
+func (t *Router) rateLimit(name string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		res := t.limiter.Allow(identity(ctx), name)
+		if res.Limit != 0 {
+			ctx.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			ctx.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			ctx.Set("X-RateLimit-Reset", strconv.FormatInt(res.Reset.Unix(), 10))
+		}
+		if !res.Allowed {
+			return rejected(ctx, res)
+		}
+		return ctx.Next()
+	}
+}
+
+// reserveExecution counts an execution of the identity
+// of the request and returns an error if the execution
+// exceeds its quotas.
+func (t *Router) reserveExecution(ctx *fiber.Ctx) error {
+	if res := t.limiter.ReserveExecution(identity(ctx)); !res.Allowed {
+		return rejected(ctx, res)
+	}
+	return nil
+}
+
+// rejected sets the Retry-After header and returns
+// the error of the rejected request.
+func rejected(ctx *fiber.Ctx, res ratelimit.Result) error {
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(res.RetryAfter)))
+	return fiber.NewError(fiber.StatusTooManyRequests, res.Err.Error())
+}
+
+func retryAfterSeconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 1)
+}
@@ -1,22 +1,40 @@
 package v1
 
 import (
+	"bufio"
+	"errors"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/zekrotja/rogu/log"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/ranna-go/ranna/internal/api/ws"
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/history"
+	"github.com/ranna-go/ranna/internal/jobs"
+	"github.com/ranna-go/ranna/internal/ratelimit"
 	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/snippets"
 	"github.com/ranna-go/ranna/internal/static"
 	"github.com/ranna-go/ranna/internal/util"
-	"github.com/ranna-go/ranna/pkg/cappedbuffer"
 	"github.com/ranna-go/ranna/pkg/models"
 )
 
+const (
+	mimeNDJSON = "application/x-ndjson"
+
+	defaultExecutionsLimit = 20
+	maxExecutionsLimit     = 100
+)
+
 var (
-	errOutputLenExceeded = fiber.NewError(fiber.StatusBadRequest, "output len exceeded")
-	errEmptyCode         = fiber.NewError(fiber.StatusBadRequest, "code is empty")
+	errOutputLenExceeded     = fiber.NewError(fiber.StatusBadRequest, "output len exceeded")
+	errEmptyCode             = fiber.NewError(fiber.StatusBadRequest, "code is empty")
+	errHistoryDisabled       = fiber.NewError(fiber.StatusNotFound, "execution history is disabled")
+	errExecutionNotFound     = fiber.NewError(fiber.StatusNotFound, "execution not found")
+	errInvalidExecutionQuery = fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
 )
 
 // Router
@@ -29,6 +47,13 @@ type Router struct {
 	spec            SpecProvider
 	cfg             ConfigProvider
 	manager         SandboxManager
+	jobs            JobManager
+	history         HistoryStore
+	snippets        SnippetManager
+	auth            Authenticator
+	limiter         RateLimiter
+	admin           AdminManager
+	scheduler       Scheduler
 	streamBufferCap int
 }
 
@@ -36,10 +61,24 @@ func (t *Router) Setup(route fiber.Router,
 	cfg ConfigProvider,
 	spec SpecProvider,
 	manager SandboxManager,
+	jobs JobManager,
+	history HistoryStore,
+	snippets SnippetManager,
+	authenticator Authenticator,
+	limiter RateLimiter,
+	admin AdminManager,
+	scheduler Scheduler,
 ) {
 	t.cfg = cfg
 	t.spec = spec
 	t.manager = manager
+	t.jobs = jobs
+	t.history = history
+	t.snippets = snippets
+	t.auth = authenticator
+	t.limiter = limiter
+	t.admin = admin
+	t.scheduler = scheduler
 
 	sbc, err := util.ParseMemoryStr(t.cfg.Config().Sandbox.StreamBufferCap)
 	if err != nil {
@@ -49,12 +88,32 @@ func (t *Router) Setup(route fiber.Router,
 	t.streamBufferCap = int(sbc)
 
 	route.Use(t.optionsBypass)
+	route.Use(t.authenticate)
 
-	route.Get("/spec", t.getSpec)
-	route.Post("/exec", t.postExec)
-	route.Get("/info", t.getInfo)
-	route.Use("/ws", ws.Upgrade())
-	route.Get("/ws", ws.Handler(cfg, manager))
+	execScope := t.requireScope(auth.ScopeExec)
+	execLimit := t.rateLimit(ratelimit.LimitExec)
+	route.Get("/spec", t.requireScope(auth.ScopeSpec), t.rateLimit("spec"), t.getSpec)
+	route.Post("/exec", execScope, execLimit, t.postExec)
+	route.Post("/exec/stream", execScope, execLimit, t.postExecStream)
+	route.Get("/info", t.requireScope(auth.ScopeInfo), t.rateLimit("info"), t.getInfo)
+	route.Post("/jobs", execScope, execLimit, t.postJob)
+	route.Get("/jobs/:id", execScope, t.rateLimit("jobs"), t.getJob)
+	route.Delete("/jobs/:id", execScope, t.rateLimit("jobs"), t.deleteJob)
+	route.Get("/executions", t.requireScope(auth.ScopeAdmin), t.rateLimit("executions"), t.getExecutions)
+	route.Get("/executions/:id", t.requireScope(auth.ScopeAdmin), t.rateLimit("executions"), t.getExecution)
+	route.Post("/snippets", execScope, t.rateLimit("snippets"), t.postSnippet)
+	route.Get("/snippets/:id", execScope, t.rateLimit("snippets"), t.getSnippet)
+	route.Post("/snippets/:id/exec", execScope, execLimit, t.postSnippetExec)
+	route.Use("/ws", execScope, t.rateLimit("ws"), ws.Upgrade())
+	route.Get("/ws", ws.Handler(spec, manager, limiter))
+
+	adminRoute := route.Group("/admin", t.requireAdmin, t.rateLimit("admin"))
+	adminRoute.Get("/sandboxes", t.getAdminSandboxes)
+	adminRoute.Delete("/sandboxes/:id", t.deleteAdminSandbox)
+	adminRoute.Post("/prepare", t.postAdminPrepare)
+	adminRoute.Post("/prepare/:language", t.postAdminPrepareSpec)
+	adminRoute.Post("/specs/reload", t.postAdminSpecsReload)
+	adminRoute.Get("/scheduler", t.getAdminScheduler)
 }
 
 func (t *Router) optionsBypass(ctx *fiber.Ctx) error {
@@ -71,7 +130,7 @@ func (t *Router) optionsBypass(ctx *fiber.Ctx) error {
 // @failure 500 {object} models.ErrorModel
 // @router /info [get]
 func (t *Router) getInfo(ctx *fiber.Ctx) (err error) {
-	sandboxInfo, err := t.manager.GetProvider().Info(ctx.Context())
+	sandboxInfo, err := t.manager.GetProvider().Info(ctx.UserContext())
 	if err != nil {
 		return err
 	}
@@ -80,6 +139,7 @@ func (t *Router) getInfo(ctx *fiber.Ctx) (err error) {
 		Version:     static.Version,
 		BuildDate:   static.BuildDate,
 		GoVersion:   runtime.Version(),
+		Pool:        t.manager.PoolInfo(),
 	}
 	return ctx.JSON(info)
 }
@@ -93,14 +153,19 @@ func (t *Router) getSpec(ctx *fiber.Ctx) (err error) {
 	return ctx.JSON(t.spec.Spec().GetSnapshot())
 }
 
-// @summary Get Spec Map
-// @description Returns the available spec map.
+// @summary Execute Code
+// @description Executes the code and returns the result. If the request accepts
+// @description application/x-ndjson, the events of the execution are streamed as
+// @description newline delimited JSON objects instead, ending with a stop or error event.
 // @accept json
 // @produce json
+// @produce application/x-ndjson
 // @param payload body models.ExecutionRequest true "The execution payload"
 // @success 200 {object} models.ExecutionResponse
 // @failure 400 {object} models.ErrorModel
+// @failure 429 {object} models.ErrorModel
 // @failure 500 {object} models.ErrorModel
+// @failure 503 {object} models.ErrorModel
 // @router /exec [post]
 func (t *Router) postExec(ctx *fiber.Ctx) (err error) {
 	req := new(models.ExecutionRequest)
@@ -108,49 +173,298 @@ func (t *Router) postExec(ctx *fiber.Ctx) (err error) {
 		return err
 	}
 
-	if req.Code == "" {
+	if req.Code == "" && len(req.Files) == 0 {
 		return errEmptyCode
 	}
 
-	cStdOut := make(chan []byte)
-	cStdErr := make(chan []byte)
+	// Interactive stdin streaming is only available
+	// via the WebSocket API.
+	req.Interactive = false
 
-	stdOut := cappedbuffer.New([]byte{}, t.streamBufferCap)
-	stdErr := cappedbuffer.New([]byte{}, t.streamBufferCap)
-	cClose := make(chan struct{}, 1)
+	if strings.Contains(ctx.Get(fiber.HeaderAccept), mimeNDJSON) {
+		return t.stream(ctx, req, ndjsonEncoder{}, mimeNDJSON)
+	}
 
-	go func() {
-		for {
-			select {
-			case <-cClose:
-				return
-			case p := <-cStdOut:
-				stdOut.Write(p)
-			case p := <-cStdErr:
-				stdErr.Write(p)
-			}
-		}
-	}()
-	defer func() {
-		cClose <- struct{}{}
-	}()
+	return t.exec(ctx, req)
+}
+
+// @summary Stream Code Execution
+// @description Executes the code and streams the events of the execution
+// @description as Server-Sent Events. The events mirror the events of the
+// @description WebSocket API and are named spawn, log, build_start, build_end,
+// @description run_start, artifact, stop and error.
+// @accept json
+// @produce text/event-stream
+// @param payload body models.ExecutionRequest true "The execution payload"
+// @success 200 {object} models.Event
+// @failure 400 {object} models.ErrorModel
+// @failure 429 {object} models.ErrorModel
+// @router /exec/stream [post]
+func (t *Router) postExecStream(ctx *fiber.Ctx) (err error) {
+	req := new(models.ExecutionRequest)
+	if err = ctx.BodyParser(req); err != nil {
+		return err
+	}
+
+	if req.Code == "" && len(req.Files) == 0 {
+		return errEmptyCode
+	}
+
+	// Interactive stdin streaming is only available
+	// via the WebSocket API.
+	req.Interactive = false
 
+	return t.stream(ctx, req, sseEncoder{}, "text/event-stream")
+}
+
+// @summary Create Execution Job
+// @description Creates an execution job which is run asynchronously
+// @description and returns its ID immediately.
+// @accept json
+// @produce json
+// @param payload body models.ExecutionRequest true "The execution payload"
+// @success 202 {object} models.Job
+// @failure 400 {object} models.ErrorModel
+// @failure 429 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /jobs [post]
+func (t *Router) postJob(ctx *fiber.Ctx) (err error) {
+	req := new(models.ExecutionRequest)
+	if err = ctx.BodyParser(req); err != nil {
+		return err
+	}
+
+	if req.Code == "" && len(req.Files) == 0 {
+		return errEmptyCode
+	}
+
+	// Interactive stdin streaming is only available
+	// via the WebSocket API.
+	req.Interactive = false
+
+	if err = t.checkLanguage(ctx, req); err != nil {
+		return err
+	}
+	if err = t.reserveExecution(ctx); err != nil {
+		return err
+	}
+
+	job, err := t.jobs.Create(withClient(ctx.UserContext(), ctx), req)
+	if err != nil {
+		t.limiter.RefundExecution(identity(ctx).Client())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// @summary Get Execution Job
+// @description Returns the status and, if finished, the result of an execution job.
+// @produce json
+// @param id path string true "The job ID"
+// @success 200 {object} models.Job
+// @failure 404 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /jobs/{id} [get]
+func (t *Router) getJob(ctx *fiber.Ctx) (err error) {
+	job, err := t.jobs.Get(ctx.Params("id"))
+	if err != nil {
+		return mapJobError(err)
+	}
+	return ctx.JSON(job)
+}
+
+// @summary Cancel Execution Job
+// @description Cancels a pending or running execution job.
+// @produce json
+// @param id path string true "The job ID"
+// @success 200 {object} models.Job
+// @failure 404 {object} models.ErrorModel
+// @failure 409 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /jobs/{id} [delete]
+func (t *Router) deleteJob(ctx *fiber.Ctx) (err error) {
+	job, err := t.jobs.Cancel(ctx.UserContext(), ctx.Params("id"))
+	if err != nil {
+		return mapJobError(err)
+	}
+	return ctx.JSON(job)
+}
+
+// @summary List Executions
+// @description Returns the recorded executions from newest to oldest.
+// @produce json
+// @param language query string false "Filter by language"
+// @param client query string false "Filter by client identity"
+// @param termination_reason query string false "Filter by termination reason"
+// @param since query string false "Only executions created at or after this time (RFC3339)"
+// @param until query string false "Only executions created before this time (RFC3339)"
+// @param offset query int false "Number of executions to skip"
+// @param limit query int false "Maximum number of executions to return (default 20, max 100)"
+// @success 200 {array} models.Execution
+// @failure 400 {object} models.ErrorModel
+// @failure 404 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /executions [get]
+func (t *Router) getExecutions(ctx *fiber.Ctx) (err error) {
+	if t.history == nil {
+		return errHistoryDisabled
+	}
+
+	filter, err := parseHistoryFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	executions, err := t.history.List(filter)
+	if err != nil {
+		return err
+	}
+	if executions == nil {
+		executions = []*models.Execution{}
+	}
+
+	return ctx.JSON(executions)
+}
+
+// @summary Get Execution
+// @description Returns a recorded execution.
+// @produce json
+// @param id path string true "The execution ID"
+// @success 200 {object} models.Execution
+// @failure 404 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /executions/{id} [get]
+func (t *Router) getExecution(ctx *fiber.Ctx) (err error) {
+	if t.history == nil {
+		return errHistoryDisabled
+	}
+
+	execution, ok, err := t.history.Get(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errExecutionNotFound
+	}
+
+	return ctx.JSON(execution)
+}
+
+// @summary Create Snippet
+// @description Stores a code snippet and returns it with its ID.
+// @accept json
+// @produce json
+// @param payload body models.SnippetRequest true "The snippet payload"
+// @success 201 {object} models.Snippet
+// @failure 400 {object} models.ErrorModel
+// @failure 413 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /snippets [post]
+func (t *Router) postSnippet(ctx *fiber.Ctx) (err error) {
+	req := new(models.SnippetRequest)
+	if err = ctx.BodyParser(req); err != nil {
+		return err
+	}
+
+	snippet, err := t.snippets.Create(req)
+	if err != nil {
+		return mapSnippetError(err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(snippet)
+}
+
+// @summary Get Snippet
+// @description Returns a stored code snippet.
+// @produce json
+// @param id path string true "The snippet ID"
+// @success 200 {object} models.Snippet
+// @failure 404 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @router /snippets/{id} [get]
+func (t *Router) getSnippet(ctx *fiber.Ctx) (err error) {
+	snippet, err := t.snippets.Get(ctx.Params("id"))
+	if err != nil {
+		return mapSnippetError(err)
+	}
+	return ctx.JSON(snippet)
+}
+
+// @summary Execute Snippet
+// @description Executes a stored code snippet.
+// @produce json
+// @param id path string true "The snippet ID"
+// @success 200 {object} models.ExecutionResponse
+// @failure 400 {object} models.ErrorModel
+// @failure 404 {object} models.ErrorModel
+// @failure 429 {object} models.ErrorModel
+// @failure 500 {object} models.ErrorModel
+// @failure 503 {object} models.ErrorModel
+// @router /snippets/{id}/exec [post]
+func (t *Router) postSnippetExec(ctx *fiber.Ctx) (err error) {
+	snippet, err := t.snippets.Get(ctx.Params("id"))
+	if err != nil {
+		return mapSnippetError(err)
+	}
+	return t.exec(ctx, snippet.ExecutionRequest())
+}
+
+// --- UTIL ---
+
+// stream runs the given request and streams the
+// events of the execution as response encoded by enc.
+func (t *Router) stream(ctx *fiber.Ctx, req *models.ExecutionRequest, enc eventEncoder, contentType string) error {
+	if err := t.checkLanguage(ctx, req); err != nil {
+		return err
+	}
+	if err := t.reserveExecution(ctx); err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set("X-Accel-Buffering", "no")
+
+	// The request context must not be used in the stream
+	// writer because it is released after the handler
+	// has returned. The user context is not bound to the
+	// request and carries the span of the request only.
+	runCtx := withClient(ctx.UserContext(), ctx)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		s := &eventStream{manager: t.manager, encoder: enc, w: w}
+		s.exec(runCtx, req)
+	})
+
+	return nil
+}
+
+// exec runs the given request and responds
+// with the result of the execution.
+func (t *Router) exec(ctx *fiber.Ctx, req *models.ExecutionRequest) (err error) {
+	if err = t.checkLanguage(ctx, req); err != nil {
+		return err
+	}
+	if err = t.reserveExecution(ctx); err != nil {
+		return err
+	}
+
+	output := sandbox.NewOutputCollector(t.streamBufferCap)
+
+	var runRes *sandbox.RunResult
 	execTime := util.MeasureTime(func() {
-		err = t.manager.RunInSandbox(ctx.Context(), req, nil, cStdOut, cStdErr)
+		runRes, err = t.manager.RunInSandbox(withClient(ctx.UserContext(), ctx), req, sandbox.RunChannels{
+			Stdout: output.Stdout,
+			Stderr: output.Stderr,
+		})
 	})
+	stdOut, stdErr := output.Close()
 
 	if err != nil {
-		if sandbox.IsSystemError(err) {
-			return err
-		}
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return mapRunError(err)
 	}
 
-	res := &models.ExecutionResponse{
-		StdOut:     stdOut.String(),
-		StdErr:     stdErr.String(),
-		ExecTimeMS: int(execTime.Milliseconds()),
-	}
+	res := runRes.ToModel(stdOut, stdErr, execTime)
 
 	if err = t.checkOutputLen(res.StdOut, res.StdErr); err != nil {
 		return
@@ -159,7 +473,69 @@ func (t *Router) postExec(ctx *fiber.Ctx) (err error) {
 	return ctx.JSON(res)
 }
 
-// --- UTIL ---
+func parseHistoryFilter(ctx *fiber.Ctx) (filter history.Filter, err error) {
+	filter = history.Filter{
+		Language:          ctx.Query("language"),
+		Client:            ctx.Query("client"),
+		TerminationReason: models.TerminationReason(ctx.Query("termination_reason")),
+		Offset:            ctx.QueryInt("offset", 0),
+		Limit:             ctx.QueryInt("limit", defaultExecutionsLimit),
+	}
+
+	if filter.Offset < 0 || filter.Limit <= 0 {
+		return filter, errInvalidExecutionQuery
+	}
+	filter.Limit = min(filter.Limit, maxExecutionsLimit)
+
+	if v := ctx.Query("since"); v != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, errInvalidExecutionQuery
+		}
+	}
+	if v := ctx.Query("until"); v != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, errInvalidExecutionQuery
+		}
+	}
+
+	return filter, nil
+}
+
+func mapRunError(err error) error {
+	if sandbox.IsSystemError(err) {
+		return err
+	}
+	if sandbox.IsUnavailableError(err) {
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	}
+	return fiber.NewError(fiber.StatusBadRequest, err.Error())
+}
+
+func mapJobError(err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, jobs.ErrNotRunning):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return err
+	}
+}
+
+func mapSnippetError(err error) error {
+	switch {
+	case errors.Is(err, snippets.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, snippets.ErrTooLarge):
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, snippets.ErrEmptyCode),
+		errors.Is(err, snippets.ErrUnsupportedLanguage),
+		errors.Is(err, snippets.ErrInvalidExpiry):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	default:
+		return err
+	}
+}
 
 func (t *Router) checkOutputLen(stdout, stderr string) (err error) {
 	maxOutLen, err := util.ParseMemoryStr(t.cfg.Config().API.MaxOutputLen)
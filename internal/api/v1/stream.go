@@ -0,0 +1,221 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+const streamKeepAliveInterval = 15 * time.Second
+
+// eventNames maps the codes of the events sent
+// by streaming endpoints to their names.
+var eventNames = map[models.EventCode]string{
+	models.EventError:      "error",
+	models.EventSpawn:      "spawn",
+	models.EventLog:        "log",
+	models.EventStop:       "stop",
+	models.EventBuildStart: "build_start",
+	models.EventBuildEnd:   "build_end",
+	models.EventRunStart:   "run_start",
+	models.EventArtifact:   "artifact",
+}
+
+// eventEncoder writes an event to a stream.
+type eventEncoder interface {
+	// encode writes the given event into w.
+	encode(w *bufio.Writer, evt models.Event) error
+	// keepAlive writes data into w which is ignored
+	// by the client to keep the connection open.
+	keepAlive(w *bufio.Writer) error
+}
+
+// sseEncoder encodes events as Server-Sent Events.
+type sseEncoder struct{}
+
+func (sseEncoder) encode(w *bufio.Writer, evt models.Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventNames[evt.Code], data)
+	return err
+}
+
+func (sseEncoder) keepAlive(w *bufio.Writer) error {
+	_, err := w.WriteString(": keep-alive\n\n")
+	return err
+}
+
+// ndjsonEncoder encodes events as newline
+// delimited JSON objects.
+type ndjsonEncoder struct{}
+
+func (ndjsonEncoder) encode(w *bufio.Writer, evt models.Event) error {
+	return json.NewEncoder(w).Encode(evt)
+}
+
+func (ndjsonEncoder) keepAlive(w *bufio.Writer) error {
+	return w.WriteByte('\n')
+}
+
+// eventStream executes requests and writes the
+// events of the executions into a response stream.
+type eventStream struct {
+	manager SandboxManager
+	encoder eventEncoder
+	w       *bufio.Writer
+
+	closed bool
+}
+
+// send writes and flushes the given event. When the
+// client has disconnected, the stream is closed and
+// all further events are dropped.
+func (t *eventStream) send(evt models.Event) {
+	if t.closed {
+		return
+	}
+	if err := t.encoder.encode(t.w, evt); err != nil {
+		t.closed = true
+		return
+	}
+	if err := t.w.Flush(); err != nil {
+		t.closed = true
+	}
+}
+
+func (t *eventStream) keepAlive() {
+	if t.closed {
+		return
+	}
+	if err := t.encoder.keepAlive(t.w); err != nil || t.w.Flush() != nil {
+		t.closed = true
+	}
+}
+
+// exec runs req and streams its events until the
+// execution has finished. When the client disconnects,
+// the sandbox is killed or, if it has not been spawned
+// yet, the execution is canceled.
+func (t *eventStream) exec(ctx context.Context, req *models.ExecutionRequest) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The spawn channel is unbuffered so that the
+	// spawn event is always sent before any output.
+	cSpn := make(chan string)
+	cStdOut := make(chan []byte)
+	cStdErr := make(chan []byte)
+	cPhase := make(chan sandbox.PhaseEvent)
+	cStop := make(chan struct{})
+	cDone := make(chan struct{})
+
+	var runId string
+
+	go func() {
+		defer close(cDone)
+
+		ticker := time.NewTicker(streamKeepAliveInterval)
+		defer ticker.Stop()
+
+		killed := false
+		for {
+			select {
+			case <-cStop:
+				return
+			case runId = <-cSpn:
+				t.send(models.Event{
+					Code: models.EventSpawn,
+					Data: models.DataSpawn{
+						DataRunId: models.DataRunId{RunId: runId},
+					},
+				})
+			case p := <-cStdOut:
+				t.send(models.Event{
+					Code: models.EventLog,
+					Data: models.DataLog{
+						DataRunId: models.DataRunId{RunId: runId},
+						StdOut:    string(p),
+					},
+				})
+			case p := <-cStdErr:
+				t.send(models.Event{
+					Code: models.EventLog,
+					Data: models.DataLog{
+						DataRunId: models.DataRunId{RunId: runId},
+						StdErr:    string(p),
+					},
+				})
+			case evt := <-cPhase:
+				t.send(evt.ToModel(0))
+			case <-ticker.C:
+				t.keepAlive()
+			}
+
+			if t.closed && !killed {
+				killed = true
+				ok := false
+				if runId != "" {
+					ok, _ = t.manager.KillAndCleanUp(context.WithoutCancel(ctx), runId)
+				}
+				if !ok {
+					cancel()
+				}
+			}
+		}
+	}()
+
+	var res *sandbox.RunResult
+	var err error
+	execTime := util.MeasureTime(func() {
+		res, err = t.manager.RunInSandbox(ctx, req, sandbox.RunChannels{
+			Spawn:  cSpn,
+			Stdout: cStdOut,
+			Stderr: cStdErr,
+			Phase:  cPhase,
+		})
+	})
+	close(cStop)
+	<-cDone
+
+	if err != nil {
+		t.send(models.Event{
+			Code: models.EventError,
+			Data: models.WsError{
+				Code:    sandbox.ErrorModel(err).Code,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	for _, artifact := range res.Artifacts {
+		t.send(models.Event{
+			Code: models.EventArtifact,
+			Data: models.DataArtifact{
+				DataRunId: models.DataRunId{RunId: runId},
+				Artifact:  artifact,
+			},
+		})
+	}
+
+	t.send(models.Event{
+		Code: models.EventStop,
+		Data: models.DataStop{
+			DataRunId:          models.DataRunId{RunId: runId},
+			ExecTimeMS:         int(execTime.Milliseconds()),
+			ExitCode:           res.ExitCode,
+			TerminationReason:  res.TerminationReason(),
+			ArtifactsTruncated: res.ArtifactsTruncated,
+			Usage:              res.Usage,
+			Cached:             res.Cached,
+		},
+	})
+}
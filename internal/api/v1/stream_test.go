@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// fakeSandboxManager runs executions which print
+// their code and, if the code is "block", block
+// until they are killed. Executions of the "c"
+// language have a build phase which fails.
+type fakeSandboxManager struct {
+	SandboxManager
+
+	cKill chan struct{}
+}
+
+func (t *fakeSandboxManager) RunInSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	chans sandbox.RunChannels,
+) (*sandbox.RunResult, error) {
+	if req.Language == "" {
+		return nil, errors.New("unsupported language spec")
+	}
+	chans.Spawn <- "run"
+	if req.Language == "c" {
+		build := &sandbox.BuildResult{RunResult: sandbox.RunResult{ExitCode: 1}, StdErr: req.Code}
+		chans.Phase <- sandbox.PhaseEvent{Phase: sandbox.PhaseBuildStart, RunId: "run"}
+		chans.Phase <- sandbox.PhaseEvent{Phase: sandbox.PhaseBuildEnd, RunId: "run", Build: build}
+		return &sandbox.RunResult{ExitCode: 1, BuildFailed: true, Build: build}, nil
+	}
+	chans.Stdout <- []byte(req.Code)
+	if req.Code == "block" {
+		chans.Stderr <- []byte("still running")
+		<-t.cKill
+		return &sandbox.RunResult{Killed: true}, nil
+	}
+	return &sandbox.RunResult{ExitCode: 1}, nil
+}
+
+func (t *fakeSandboxManager) KillAndCleanUp(ctx context.Context, id string) (bool, error) {
+	close(t.cKill)
+	return true, nil
+}
+
+var rxExecTime = regexp.MustCompile(`"exectimems":\d+`)
+
+// normalizeExecTime sets all execution times
+// in the given stream to 0.
+func normalizeExecTime(stream string) string {
+	return rxExecTime.ReplaceAllString(stream, `"exectimems":0`)
+}
+
+// failingWriter fails all writes after n bytes
+// have been written.
+type failingWriter struct {
+	n int
+}
+
+func (t *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > t.n {
+		return 0, errors.New("connection closed")
+	}
+	t.n -= len(p)
+	return len(p), nil
+}
+
+func TestEventStreamSSE(t *testing.T) {
+	mgr := &fakeSandboxManager{cKill: make(chan struct{})}
+
+	var buf bytes.Buffer
+	s := &eventStream{manager: mgr, encoder: sseEncoder{}, w: bufio.NewWriter(&buf)}
+	s.exec(context.Background(), &models.ExecutionRequest{Language: "python", Code: "hello"})
+
+	events := strings.Split(strings.TrimSpace(normalizeExecTime(buf.String())), "\n\n")
+	expected := []string{
+		`event: spawn` + "\n" + `data: {"code":2,"data":{"runid":"run"}}`,
+		`event: log` + "\n" + `data: {"code":3,"data":{"runid":"run","stdout":"hello"}}`,
+		`event: stop` + "\n" + `data: {"code":4,"data":{"runid":"run","exectimems":0,"exit_code":1,"termination_reason":"exited"}}`,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("invalid events: %q", events)
+	}
+	for i, evt := range events {
+		if evt != expected[i] {
+			t.Errorf("invalid event %d: %s (expected: %s)", i, evt, expected[i])
+		}
+	}
+
+	buf.Reset()
+	s = &eventStream{manager: mgr, encoder: sseEncoder{}, w: bufio.NewWriter(&buf)}
+	s.exec(context.Background(), &models.ExecutionRequest{Code: "hello"})
+	if !strings.HasPrefix(buf.String(), "event: error\n") ||
+		!strings.Contains(buf.String(), `"code":400`) {
+		t.Errorf("invalid error event: %s", buf.String())
+	}
+}
+
+func TestEventStreamBuildFailed(t *testing.T) {
+	mgr := &fakeSandboxManager{cKill: make(chan struct{})}
+
+	var buf bytes.Buffer
+	s := &eventStream{manager: mgr, encoder: sseEncoder{}, w: bufio.NewWriter(&buf)}
+	s.exec(context.Background(), &models.ExecutionRequest{Language: "c", Code: "syntax error"})
+
+	events := strings.Split(strings.TrimSpace(normalizeExecTime(buf.String())), "\n\n")
+	expected := []string{
+		`event: spawn` + "\n" + `data: {"code":2,"data":{"runid":"run"}}`,
+		`event: build_start` + "\n" + `data: {"code":5,"data":{"runid":"run"}}`,
+		`event: build_end` + "\n" + `data: {"code":6,"data":{"runid":"run","build_stdout":"","build_stderr":"syntax error",` +
+			`"build_exit_code":1,"build_time_ms":0,"build_termination_reason":"exited"}}`,
+		`event: stop` + "\n" + `data: {"code":4,"data":{"runid":"run","exectimems":0,"exit_code":1,"termination_reason":"build_failed"}}`,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("invalid events: %q", events)
+	}
+	for i, evt := range events {
+		if evt != expected[i] {
+			t.Errorf("invalid event %d: %s (expected: %s)", i, evt, expected[i])
+		}
+	}
+}
+
+func TestEventStreamDisconnect(t *testing.T) {
+	mgr := &fakeSandboxManager{cKill: make(chan struct{})}
+
+	// The client disconnects after the spawn and
+	// the first log event have been received.
+	w := &failingWriter{n: 150}
+	s := &eventStream{manager: mgr, encoder: sseEncoder{}, w: bufio.NewWriterSize(w, 16)}
+	s.exec(context.Background(), &models.ExecutionRequest{Language: "python", Code: "block"})
+
+	if !s.closed {
+		t.Error("stream has not been closed")
+	}
+	select {
+	case <-mgr.cKill:
+	default:
+		t.Error("sandbox has not been killed")
+	}
+}
+
+func TestEventStreamNDJSON(t *testing.T) {
+	mgr := &fakeSandboxManager{cKill: make(chan struct{})}
+
+	var buf bytes.Buffer
+	s := &eventStream{manager: mgr, encoder: ndjsonEncoder{}, w: bufio.NewWriter(&buf)}
+	s.exec(context.Background(), &models.ExecutionRequest{Language: "python", Code: "hello"})
+
+	expected := `{"code":2,"data":{"runid":"run"}}` + "\n" +
+		`{"code":3,"data":{"runid":"run","stdout":"hello"}}` + "\n" +
+		`{"code":4,"data":{"runid":"run","exectimems":0,"exit_code":1,"termination_reason":"exited"}}` + "\n"
+	if normalizeExecTime(buf.String()) != expected {
+		t.Errorf("invalid stream: %s", buf.String())
+	}
+}
@@ -3,6 +3,7 @@ package ws
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func Upgrade() fiber.Handler {
@@ -11,13 +12,13 @@ func Upgrade() fiber.Handler {
 			if ip := c.IP(); ip != "" {
 				c.Locals("ip", ip)
 			}
+			c.Locals("spancontext", trace.SpanContextFromContext(c.UserContext()))
 			return c.Next()
 		}
 		return fiber.ErrUpgradeRequired
 	}
 }
 
-func Handler(cfg ConfigProvider, manager SandboxManager) fiber.Handler {
-	rlm := NewRateLimitManager(cfg)
-	return newSession(rlm, manager).Handler()
+func Handler(spec SpecProvider, manager SandboxManager, limiter RateLimiter) fiber.Handler {
+	return newSession(limiter, spec, manager).Handler()
 }
@@ -3,7 +3,11 @@ package ws
 import (
 	"context"
 
+	"github.com/ranna-go/ranna/internal/auth"
 	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/ratelimit"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/spec"
 	"github.com/ranna-go/ranna/pkg/models"
 )
 
@@ -11,13 +15,22 @@ type ConfigProvider interface {
 	Config() *config.Config
 }
 
+type SpecProvider interface {
+	Spec() *spec.SafeSpecMap
+}
+
 type SandboxManager interface {
 	RunInSandbox(
 		ctx context.Context,
 		req *models.ExecutionRequest,
-		cSpn chan string,
-		cOut chan []byte,
-		cErr chan []byte,
-	) (err error)
+		chans sandbox.RunChannels,
+	) (res *sandbox.RunResult, err error)
 	KillAndCleanUp(ctx context.Context, id string) (bool, error)
+	WriteStdin(id string, p []byte) (bool, error)
+	CloseStdin(id string) (bool, error)
+}
+
+type RateLimiter interface {
+	Allow(id *auth.Identity, name string) ratelimit.Result
+	ReserveExecution(id *auth.Identity) ratelimit.Result
 }
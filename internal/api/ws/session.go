@@ -1,7 +1,6 @@
 package ws
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -12,7 +11,12 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ranna-go/ranna/internal/metrics"
+	"github.com/ranna-go/ranna/internal/ratelimit"
 	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/tracing"
 	"github.com/ranna-go/ranna/internal/util"
 	"github.com/ranna-go/ranna/pkg/models"
 )
@@ -23,19 +27,30 @@ var sessionPool = sync.Pool{
 	},
 }
 
+// opLimits maps the operations to the names of
+// the rate limits they are taken from.
+var opLimits = map[models.OpCode]string{
+	models.OpPing:  "ws.ping",
+	models.OpExec:  ratelimit.LimitExec,
+	models.OpKill:  "ws.kill",
+	models.OpStdin: "ws.stdin",
+}
+
 type session struct {
+	spec    SpecProvider
 	manager SandboxManager
 	logger  rogu.Logger
 	conn    *websocket.Conn
-	rlm     *RateLimitManager
+	limiter RateLimiter
 }
 
-func newSession(rlm *RateLimitManager, manager SandboxManager) (t *session) {
+func newSession(limiter RateLimiter, spec SpecProvider, manager SandboxManager) (t *session) {
 	t = sessionPool.Get().(*session)
 	t.conn = nil
+	t.spec = spec
 	t.manager = manager
 	t.logger = log.Tagged("WS")
-	t.rlm = rlm
+	t.limiter = limiter
 	return t
 }
 
@@ -50,8 +65,12 @@ func (t *session) Handler() fiber.Handler {
 	return websocket.New(func(c *websocket.Conn) {
 		t.logger.Debug().
 			Field("addr", getAddr(c)).
+			Field("client", getClient(c)).
 			Msg("new websocket connection")
 
+		metrics.WSConnections.Inc()
+		defer metrics.WSConnections.Dec()
+
 		t.conn = c
 		var (
 			typ   int
@@ -98,7 +117,7 @@ func (t *session) HandleOp(msg []byte) (nonce int, err error) {
 		return 0, err
 	}
 
-	if !t.rlm.GetLimiter(t.conn, op.Op).Allow() {
+	if !t.limiter.Allow(getLimitIdentity(t.conn), opLimits[op.Op]).Allowed {
 		return op.Nonce, models.ErrRateLimited
 	}
 
@@ -122,6 +141,12 @@ func (t *session) HandleOp(msg []byte) (nonce int, err error) {
 		if err == nil {
 			err = t.handleKill(eop)
 		}
+	case models.OpStdin:
+		var eop models.OperationStdin
+		err = json.Unmarshal(msg, &eop)
+		if err == nil {
+			err = t.handleStdin(eop)
+		}
 	default:
 		err = models.ErrInvalidOpCode
 	}
@@ -130,15 +155,35 @@ func (t *session) HandleOp(msg []byte) (nonce int, err error) {
 }
 
 func (t *session) handleExec(op models.OperationExec) (err error) {
-	if op.Args.Code == "" {
+	ctx, span := startSpan(t.conn, "ws.exec",
+		attribute.String("language", op.Args.Language),
+		attribute.Int("nonce", op.Nonce))
+	defer func() { tracing.End(span, err) }()
+
+	if op.Args.Code == "" && len(op.Args.Files) == 0 {
 		return models.ErrEmptyCode
 	}
 
+	if id := getIdentity(t.conn); id != nil && !id.CanExec(t.spec.Spec(), op.Args.Language) {
+		return models.ErrLanguageNotAllowed
+	}
+
+	if res := t.limiter.ReserveExecution(getLimitIdentity(t.conn)); !res.Allowed {
+		return models.WsError{Code: http.StatusTooManyRequests, Message: res.Err.Error()}
+	}
+
+	cQueued := make(chan int)
 	cSpn := make(chan string, 1)
 	cStdOut := make(chan []byte)
 	cStdErr := make(chan []byte)
+	cPhase := make(chan sandbox.PhaseEvent)
 	cStop := make(chan struct{}, 1)
 
+	var cStats chan models.ResourceUsage
+	if op.Args.LiveStats {
+		cStats = make(chan models.ResourceUsage)
+	}
+
 	var runId string
 
 	go func() {
@@ -146,6 +191,14 @@ func (t *session) handleExec(op models.OperationExec) (err error) {
 			select {
 			case <-cStop:
 				return
+			case pos := <-cQueued:
+				err = t.Send(models.Event{
+					Code:  models.EventQueued,
+					Nonce: op.Nonce,
+					Data: models.DataQueued{
+						Position: pos,
+					},
+				})
 			case runId = <-cSpn:
 				err = t.Send(models.Event{
 					Code:  models.EventSpawn,
@@ -178,6 +231,19 @@ func (t *session) handleExec(op models.OperationExec) (err error) {
 						StdErr: string(p),
 					},
 				})
+			case evt := <-cPhase:
+				err = t.Send(evt.ToModel(op.Nonce))
+			case usage := <-cStats:
+				err = t.Send(models.Event{
+					Code:  models.EventStats,
+					Nonce: op.Nonce,
+					Data: models.DataStats{
+						DataRunId: models.DataRunId{
+							RunId: runId,
+						},
+						ResourceUsage: usage,
+					},
+				})
 			}
 			if err != nil {
 				t.logger.Error().Err(err).Msg("Failed sending event")
@@ -192,17 +258,44 @@ func (t *session) handleExec(op models.OperationExec) (err error) {
 		cStop <- struct{}{}
 	}()
 
+	var res *sandbox.RunResult
 	execTime := util.MeasureTime(func() {
-		err = t.manager.RunInSandbox(context.TODO(), &op.Args, cSpn, cStdOut, cStdErr)
+		res, err = t.manager.RunInSandbox(withClient(ctx, t.conn), &op.Args, sandbox.RunChannels{
+			Queued: cQueued,
+			Spawn:  cSpn,
+			Stdout: cStdOut,
+			Stderr: cStdErr,
+			Phase:  cPhase,
+			Stats:  cStats,
+		})
 	})
 
 	if err != nil {
 		if sandbox.IsSystemError(err) {
 			return t.SendError(op.Nonce, err)
 		}
+		if sandbox.IsUnavailableError(err) {
+			return t.SendError(op.Nonce, models.WsError{Code: http.StatusServiceUnavailable, Message: err.Error()})
+		}
 		return t.SendError(op.Nonce, models.WsError{Code: http.StatusBadRequest, Message: err.Error()})
 	}
 
+	for _, artifact := range res.Artifacts {
+		err = t.Send(models.Event{
+			Code:  models.EventArtifact,
+			Nonce: op.Nonce,
+			Data: models.DataArtifact{
+				DataRunId: models.DataRunId{
+					RunId: runId,
+				},
+				Artifact: artifact,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	err = t.Send(models.Event{
 		Code:  models.EventStop,
 		Nonce: op.Nonce,
@@ -210,7 +303,12 @@ func (t *session) handleExec(op models.OperationExec) (err error) {
 			DataRunId: models.DataRunId{
 				RunId: runId,
 			},
-			ExecTimeMS: int(execTime.Milliseconds()),
+			ExecTimeMS:         int(execTime.Milliseconds()),
+			ExitCode:           res.ExitCode,
+			TerminationReason:  res.TerminationReason(),
+			ArtifactsTruncated: res.ArtifactsTruncated,
+			Usage:              res.Usage,
+			Cached:             res.Cached,
 		},
 	})
 
@@ -218,7 +316,10 @@ func (t *session) handleExec(op models.OperationExec) (err error) {
 }
 
 func (t *session) handleKill(op models.OperationKill) (err error) {
-	ok, err := t.manager.KillAndCleanUp(context.TODO(), op.Args.RunId)
+	ctx, span := startSpan(t.conn, "ws.kill", attribute.String("runid", op.Args.RunId))
+	defer func() { tracing.End(span, err) }()
+
+	ok, err := t.manager.KillAndCleanUp(ctx, op.Args.RunId)
 	if err != nil {
 		return
 	}
@@ -227,3 +328,20 @@ func (t *session) handleKill(op models.OperationKill) (err error) {
 	}
 	return
 }
+
+func (t *session) handleStdin(op models.OperationStdin) (err error) {
+	var ok bool
+	if op.Args.Stdin != "" {
+		ok, err = t.manager.WriteStdin(op.Args.RunId, []byte(op.Args.Stdin))
+		if err != nil || !ok {
+			return mapStdinErr(ok, err)
+		}
+	}
+	if op.Args.Close {
+		ok, err = t.manager.CloseStdin(op.Args.RunId)
+		if err != nil || !ok {
+			return mapStdinErr(ok, err)
+		}
+	}
+	return nil
+}
@@ -1,11 +1,22 @@
 package ws
 
 import (
+	"context"
+	"net/http"
 	"strings"
 
 	"github.com/gofiber/websocket/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
 )
 
+var tracer = otel.Tracer("github.com/ranna-go/ranna/internal/api/ws")
+
 func getAddr(c *websocket.Conn) string {
 	if ip, ok := c.Locals("ip").(string); ok && ip != "" {
 		return ip
@@ -13,3 +24,56 @@ func getAddr(c *websocket.Conn) string {
 	addr := c.RemoteAddr().String()
 	return addr[:strings.LastIndex(addr, ":")]
 }
+
+// getIdentity returns the identity the connection
+// has been authenticated with or nil if not set.
+func getIdentity(c *websocket.Conn) *auth.Identity {
+	id, _ := c.Locals("identity").(*auth.Identity)
+	return id
+}
+
+// getClient returns the client identity of the
+// connection used for logging, fair queueing and
+// rate limiting.
+func getClient(c *websocket.Conn) string {
+	if id := getIdentity(c); id != nil {
+		return id.Client()
+	}
+	return getAddr(c)
+}
+
+// getLimitIdentity returns the identity of the
+// connection the rate limits and quotas apply to.
+func getLimitIdentity(c *websocket.Conn) *auth.Identity {
+	if id := getIdentity(c); id != nil {
+		return id
+	}
+	return &auth.Identity{Addr: getAddr(c)}
+}
+
+// withClient returns a copy of parent carrying the
+// identity of the connection.
+func withClient(parent context.Context, c *websocket.Conn) context.Context {
+	if id := getIdentity(c); id != nil {
+		parent = auth.WithIdentity(parent, id)
+	}
+	return sandbox.WithClient(parent, getClient(c))
+}
+
+// startSpan starts a span for an operation of the
+// connection as child of the span of the upgrade request.
+func startSpan(c *websocket.Conn, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	sc, _ := c.Locals("spancontext").(trace.SpanContext)
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func mapStdinErr(ok bool, err error) error {
+	if !ok {
+		return models.ErrSandboxNotRunning
+	}
+	if sandbox.IsSystemError(err) {
+		return err
+	}
+	return models.WsError{Code: http.StatusBadRequest, Message: err.Error()}
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type staticConfig struct {
+	c *config.Config
+}
+
+func (t staticConfig) Config() *config.Config { return t.c }
+
+func newTestAuthenticator(t *testing.T, allowUnauthenticated bool) *Authenticator {
+	hash := sha256.Sum256([]byte("hashed-key"))
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yaml": `
+- name: admin
+  key: admin-key
+  scopes: [admin]
+- name: hashed
+  key: sha256:` + hex.EncodeToString(hash[:]) + `
+  scopes: [exec]
+  languages: [go]
+`,
+		"b.json": `[{"name": "expired", "key": "expired-key", "scopes": ["exec"], "expires": "2020-01-01T00:00:00Z"}]`,
+		"README": "not a keys file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := NewAuthenticator(staticConfig{&config.Config{Auth: config.Auth{
+		KeysPath:              dir,
+		AllowUnauthenticated:  allowUnauthenticated,
+		UnauthenticatedScopes: "spec info",
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAuthenticate(t *testing.T) {
+	a := newTestAuthenticator(t, true)
+
+	expect := func(header, name string, expectedErr error) *Identity {
+		t.Helper()
+		id, err := a.Authenticate(header, "1.2.3.4")
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("invalid error for %q: %v (expected: %v)", header, err, expectedErr)
+		}
+		if err == nil && id.Name != name {
+			t.Errorf("invalid identity for %q: %s (expected: %s)", header, id.Name, name)
+		}
+		return id
+	}
+
+	id := expect("", "", nil)
+	if id.Authenticated() || id.Client() != "1.2.3.4" {
+		t.Errorf("invalid unauthenticated identity: %+v", id)
+	}
+	if id.HasScope(ScopeExec) || !id.HasScope(ScopeSpec) {
+		t.Errorf("invalid unauthenticated scopes: %v", id.Scopes)
+	}
+
+	id = expect("Bearer admin-key", "admin", nil)
+	if id.Client() != "key:admin" || !id.HasScope(ScopeExec) || !id.HasScope(ScopeAdmin) {
+		t.Errorf("invalid admin identity: %+v", id)
+	}
+
+	expect("hashed-key", "hashed", nil)
+	expect("bearer  hashed-key ", "hashed", nil)
+	expect("Bearer invalid", "", ErrInvalidKey)
+	expect("expired-key", "", ErrKeyExpired)
+
+	a = newTestAuthenticator(t, false)
+	if _, err := a.Authenticate("", "1.2.3.4"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("invalid error for unauthenticated request: %v", err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	cases := map[string]string{
+		"empty name":     `[{"key": "a", "scopes": ["exec"]}]`,
+		"empty key":      `[{"name": "a", "scopes": ["exec"]}]`,
+		"invalid scope":  `[{"name": "a", "key": "a", "scopes": ["root"]}]`,
+		"invalid hash":   `[{"name": "a", "key": "sha256:abc", "scopes": ["exec"]}]`,
+		"duplicate name": `[{"name": "a", "key": "a"}, {"name": "a", "key": "b"}]`,
+		"duplicate key":  `[{"name": "a", "key": "a"}, {"name": "b", "key": "a"}]`,
+	}
+
+	for name, content := range cases {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := NewAuthenticator(staticConfig{&config.Config{Auth: config.Auth{KeysPath: path}}})
+		if err == nil {
+			t.Errorf("%s: no error returned", name)
+		}
+	}
+}
+
+func TestCanExec(t *testing.T) {
+	specs := spec.NewSafeSpecMap(models.SpecMap{
+		"go":     {Image: "golang"},
+		"golang": {Use: "go"},
+		"python": {Image: "python"},
+	})
+
+	id := &Identity{Name: "a", Scopes: []Scope{ScopeExec}, Languages: []string{"go"}}
+	for lang, expected := range map[string]bool{"go": true, "golang": true, "python": false, "rust": false} {
+		if ok := id.CanExec(specs, lang); ok != expected {
+			t.Errorf("CanExec(%s) = %t (expected: %t)", lang, ok, expected)
+		}
+	}
+
+	id = &Identity{Scopes: []Scope{ScopeExec}}
+	if !id.CanExec(specs, "python") {
+		t.Error("identity without language restrictions can not execute python")
+	}
+
+	id = &Identity{Scopes: []Scope{ScopeSpec}}
+	if id.CanExec(specs, "python") {
+		t.Error("identity without exec scope can execute python")
+	}
+}
@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+const hashPrefix = "sha256:"
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrInvalidKey      = errors.New("invalid API key")
+	ErrKeyExpired      = errors.New("API key has expired")
+)
+
+// Key is the definition of an API key.
+//
+// Key contains either the key itself or its
+// hex encoded SHA-256 hash prefixed with "sha256:".
+type Key struct {
+	Name      string     `json:"name"`
+	Key       string     `json:"key"`
+	Scopes    []Scope    `json:"scopes"`
+	Languages []string   `json:"languages,omitempty"`
+	Expires   *time.Time `json:"expires,omitempty"`
+	Quota     Quota      `json:"quota,omitempty"`
+}
+
+// Authenticator validates API keys defined in a
+// keys file or a directory of keys files.
+type Authenticator struct {
+	path                  string
+	allowUnauthenticated  bool
+	unauthenticatedScopes []Scope
+
+	mtx  sync.RWMutex
+	keys map[[sha256.Size]byte]*Key
+}
+
+// NewAuthenticator returns a new instance of
+// Authenticator and loads the configured keys.
+//
+// If no keys path is configured, all requests
+// are handled as unauthenticated.
+func NewAuthenticator(cfg ConfigProvider) (t *Authenticator, err error) {
+	c := cfg.Config().Auth
+
+	t = &Authenticator{
+		path:                 c.KeysPath,
+		allowUnauthenticated: c.AllowUnauthenticated,
+		keys:                 make(map[[sha256.Size]byte]*Key),
+	}
+
+	for _, s := range strings.Fields(c.UnauthenticatedScopes) {
+		scope := Scope(s)
+		if !isValidScope(scope) {
+			return nil, fmt.Errorf("invalid scope: %s", s)
+		}
+		t.unauthenticatedScopes = append(t.unauthenticatedScopes, scope)
+	}
+
+	if err = t.Load(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Load (re-)reads the keys from the configured keys
+// file or all .yaml, .yml and .json files in the
+// configured keys directory.
+func (t *Authenticator) Load() error {
+	if t.path == "" {
+		return nil
+	}
+
+	files := []string{t.path}
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(t.path)
+		if err != nil {
+			return err
+		}
+		files = files[:0]
+		for _, e := range entries {
+			switch filepath.Ext(e.Name()) {
+			case ".yaml", ".yml", ".json":
+				if !e.IsDir() {
+					files = append(files, filepath.Join(t.path, e.Name()))
+				}
+			}
+		}
+	}
+
+	keys := make(map[[sha256.Size]byte]*Key)
+	names := make(map[string]struct{})
+	for _, file := range files {
+		if err = readKeys(file, keys, names); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	t.mtx.Lock()
+	t.keys = keys
+	t.mtx.Unlock()
+
+	return nil
+}
+
+// Authenticate returns the identity of a request by
+// the passed Authorization header value and address
+// of the client.
+//
+// The header value may either be the key itself or
+// the key prefixed with "Bearer ". If the header is
+// empty, the unauthenticated identity is returned if
+// unauthenticated access is allowed.
+func (t *Authenticator) Authenticate(header, addr string) (*Identity, error) {
+	token := strings.TrimSpace(header)
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+
+	if token == "" {
+		if !t.allowUnauthenticated {
+			return nil, ErrUnauthenticated
+		}
+		return &Identity{
+			Addr:   addr,
+			Scopes: t.unauthenticatedScopes,
+		}, nil
+	}
+
+	t.mtx.RLock()
+	key, ok := t.keys[sha256.Sum256([]byte(token))]
+	t.mtx.RUnlock()
+
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	if key.Expires != nil && time.Now().After(*key.Expires) {
+		return nil, ErrKeyExpired
+	}
+
+	return &Identity{
+		Name:      key.Name,
+		Addr:      addr,
+		Scopes:    key.Scopes,
+		Languages: key.Languages,
+		Quota:     key.Quota,
+	}, nil
+}
+
+// readKeys parses the list of keys in the given file
+// and adds them to keys by the hash of the key.
+func readKeys(file string, keys map[[sha256.Size]byte]*Key, names map[string]struct{}) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var list []*Key
+	if err = yaml.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	for _, key := range list {
+		if key.Name == "" {
+			return errors.New("key name is empty")
+		}
+		if _, ok := names[key.Name]; ok {
+			return fmt.Errorf("duplicate key name: %s", key.Name)
+		}
+		names[key.Name] = struct{}{}
+
+		for _, scope := range key.Scopes {
+			if !isValidScope(scope) {
+				return fmt.Errorf("key %s: invalid scope: %s", key.Name, scope)
+			}
+		}
+
+		hash, err := hashKey(key.Key)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", key.Name, err)
+		}
+		if _, ok := keys[hash]; ok {
+			return fmt.Errorf("key %s: duplicate key", key.Name)
+		}
+		keys[hash] = key
+	}
+
+	return nil
+}
+
+// hashKey returns the SHA-256 hash of a plain key or
+// decodes the hash of a key prefixed with "sha256:".
+func hashKey(key string) (hash [sha256.Size]byte, err error) {
+	if key == "" {
+		return hash, errors.New("key is empty")
+	}
+
+	if !strings.HasPrefix(key, hashPrefix) {
+		return sha256.Sum256([]byte(key)), nil
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(key, hashPrefix))
+	if err != nil || len(b) != sha256.Size {
+		return hash, errors.New("invalid key hash")
+	}
+	copy(hash[:], b)
+	return hash, nil
+}
+
+func isValidScope(scope Scope) bool {
+	return slices.Contains([]Scope{ScopeExec, ScopeSpec, ScopeInfo, ScopeAdmin}, scope)
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ranna-go/ranna/internal/spec"
+)
+
+// Scope grants access to a group of endpoints.
+type Scope string
+
+const (
+	// ScopeExec grants executing code.
+	ScopeExec Scope = "exec"
+	// ScopeSpec grants reading the spec map.
+	ScopeSpec Scope = "spec"
+	// ScopeInfo grants reading system information.
+	ScopeInfo Scope = "info"
+	// ScopeAdmin grants all scopes as well as
+	// access to administrative endpoints.
+	ScopeAdmin Scope = "admin"
+)
+
+// Identity is the authenticated identity of a request.
+type Identity struct {
+	// Name is the name of the API key used to authenticate.
+	// It is empty for unauthenticated requests.
+	Name string
+	// Addr is the address of the client.
+	Addr string
+	// Scopes contains the scopes granted to the identity.
+	Scopes []Scope
+	// Languages contains the languages the identity is
+	// allowed to execute. Empty means all languages.
+	Languages []string
+	// Quota overrides the configured execution quotas.
+	Quota Quota
+}
+
+// Quota limits the executions of an identity per day
+// and month. Zero values fall back to the configured
+// quotas, negative values disable the respective quota.
+type Quota struct {
+	DailyExecutions   int `json:"daily_executions,omitempty"`
+	MonthlyExecutions int `json:"monthly_executions,omitempty"`
+	DailyCPUSeconds   int `json:"daily_cpu_seconds,omitempty"`
+	MonthlyCPUSeconds int `json:"monthly_cpu_seconds,omitempty"`
+}
+
+// Authenticated returns true if the identity has
+// been authenticated by an API key.
+func (t *Identity) Authenticated() bool {
+	return t.Name != ""
+}
+
+// Client returns the client identity used for logging,
+// fair queueing and rate limiting. Authenticated
+// identities are identified by the name of their key,
+// unauthenticated ones by their address.
+func (t *Identity) Client() string {
+	if t.Authenticated() {
+		return "key:" + t.Name
+	}
+	return t.Addr
+}
+
+// HasScope returns true if the given scope
+// is granted to the identity.
+func (t *Identity) HasScope(scope Scope) bool {
+	return slices.Contains(t.Scopes, scope) || slices.Contains(t.Scopes, ScopeAdmin)
+}
+
+// CanExec returns true if the identity is allowed to
+// execute code of the given language. Aliases are
+// allowed if either the alias or the spec it points
+// to is allowed.
+func (t *Identity) CanExec(specs *spec.SafeSpecMap, language string) bool {
+	if !t.HasScope(ScopeExec) {
+		return false
+	}
+	if len(t.Languages) == 0 || slices.Contains(t.Languages, language) {
+		return true
+	}
+	if key, _, ok := specs.Resolve(language); ok {
+		return slices.Contains(t.Languages, key)
+	}
+	return false
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the
+// given identity.
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity passed with
+// ctx or nil if not set.
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
@@ -0,0 +1,9 @@
+package auth
+
+import (
+	"github.com/ranna-go/ranna/internal/config"
+)
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
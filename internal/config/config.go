@@ -17,24 +17,129 @@ type WebSocket struct {
 	RateLimit Ratelimit `json:"ratelimit" yaml:"ratelimit"`
 }
 
+type GRPC struct {
+	BindAddress string `config:"api.grpc.bindaddress" json:"bindaddress" yaml:"bindaddress"`
+}
+
 type API struct {
 	BindAddress    string    `config:"api.bindaddress,required" json:"bindaddress" yaml:"api"`
 	MaxOutputLen   string    `config:"api.maxoutputlen" json:"maxoutputlen" yaml:"maxoutputlen"`
 	TrustedProxies string    `config:"api.trustedproxies" json:"trustedproxies" yaml:"trustedproxies"`
 	WS             WebSocket `json:"ws" yaml:"ws"`
+	GRPC           GRPC      `json:"grpc" yaml:"grpc"`
+}
+
+type Ulimits struct {
+	NoFile int64  `config:"sandbox.ulimits.nofile" json:"nofile" yaml:"nofile"`
+	FSize  string `config:"sandbox.ulimits.fsize" json:"fsize" yaml:"fsize"`
+	NProc  int64  `config:"sandbox.ulimits.nproc" json:"nproc" yaml:"nproc"`
+}
+
+type Hardening struct {
+	ReadOnlyRootFS   bool   `config:"sandbox.hardening.readonlyrootfs" json:"readonlyrootfs" yaml:"readonlyrootfs"`
+	TmpfsSize        string `config:"sandbox.hardening.tmpfssize" json:"tmpfssize" yaml:"tmpfssize"`
+	DropCapabilities bool   `config:"sandbox.hardening.dropcapabilities" json:"dropcapabilities" yaml:"dropcapabilities"`
+	NoNewPrivileges  bool   `config:"sandbox.hardening.nonewprivileges" json:"nonewprivileges" yaml:"nonewprivileges"`
+	SeccompProfile   string `config:"sandbox.hardening.seccompprofile" json:"seccompprofile" yaml:"seccompprofile"`
+	UID              int    `config:"sandbox.hardening.uid" json:"uid" yaml:"uid"`
+	GID              int    `config:"sandbox.hardening.gid" json:"gid" yaml:"gid"`
+}
+
+type Artifacts struct {
+	MaxCount int    `config:"sandbox.artifacts.maxcount" json:"maxcount" yaml:"maxcount"`
+	MaxSize  string `config:"sandbox.artifacts.maxsize" json:"maxsize" yaml:"maxsize"`
+}
+
+type Queue struct {
+	MaxConcurrent  int `config:"sandbox.queue.maxconcurrent" json:"maxconcurrent" yaml:"maxconcurrent"`
+	MaxLength      int `config:"sandbox.queue.maxlength" json:"maxlength" yaml:"maxlength"`
+	TimeoutSeconds int `config:"sandbox.queue.timeoutseconds" json:"timeoutseconds" yaml:"timeoutseconds"`
+}
+
+type Cache struct {
+	TTLSeconds int    `config:"sandbox.cache.ttlseconds" json:"ttlseconds" yaml:"ttlseconds"`
+	MaxSize    string `config:"sandbox.cache.maxsize" json:"maxsize" yaml:"maxsize"`
 }
 
 type Sandbox struct {
-	Runtime          string `config:"sandbox.runtime" json:"runtime" yaml:"runtime"`
-	EnableNetworking bool   `config:"sandbox.enablenetworking" json:"enablenetworking" yaml:"enablenetworking"`
-	Memory           string `config:"sandbox.memory" json:"memory" yaml:"memory"`
-	TimeoutSeconds   int    `config:"sandbox.timeoutseconds" json:"executiontimeoutseconds" yaml:"executiontimeoutseconds"`
-	StreamBufferCap  string `config:"sandbox.streambuffercap" json:"streambuffercap" yaml:"streambuffercap"`
+	Runtime           string    `config:"sandbox.runtime" json:"runtime" yaml:"runtime"`
+	EnableNetworking  bool      `config:"sandbox.enablenetworking" json:"enablenetworking" yaml:"enablenetworking"`
+	Memory            string    `config:"sandbox.memory" json:"memory" yaml:"memory"`
+	MaxMemory         string    `config:"sandbox.maxmemory" json:"maxmemory" yaml:"maxmemory"`
+	TimeoutSeconds    int       `config:"sandbox.timeoutseconds" json:"executiontimeoutseconds" yaml:"executiontimeoutseconds"`
+	MaxTimeoutSeconds int       `config:"sandbox.maxtimeoutseconds" json:"maxtimeoutseconds" yaml:"maxtimeoutseconds"`
+	CPUs              float64   `config:"sandbox.cpus" json:"cpus" yaml:"cpus"`
+	MaxCPUs           float64   `config:"sandbox.maxcpus" json:"maxcpus" yaml:"maxcpus"`
+	CPUShares         int64     `config:"sandbox.cpushares" json:"cpushares" yaml:"cpushares"`
+	CPUSetCPUs        string    `config:"sandbox.cpusetcpus" json:"cpusetcpus" yaml:"cpusetcpus"`
+	PidsLimit         int64     `config:"sandbox.pidslimit" json:"pidslimit" yaml:"pidslimit"`
+	Ulimits           Ulimits   `json:"ulimits" yaml:"ulimits"`
+	Hardening         Hardening `json:"hardening" yaml:"hardening"`
+	Artifacts         Artifacts `json:"artifacts" yaml:"artifacts"`
+	Queue             Queue     `json:"queue" yaml:"queue"`
+	Cache             Cache     `json:"cache" yaml:"cache"`
+	StreamBufferCap   string    `config:"sandbox.streambuffercap" json:"streambuffercap" yaml:"streambuffercap"`
+}
+
+type Jobs struct {
+	ResultTTLSeconds int `config:"jobs.resultttlseconds" json:"resultttlseconds" yaml:"resultttlseconds"`
+}
+
+type Snippets struct {
+	Store                string `config:"snippets.store" json:"store" yaml:"store"`
+	Path                 string `config:"snippets.path" json:"path" yaml:"path"`
+	MaxSize              string `config:"snippets.maxsize" json:"maxsize" yaml:"maxsize"`
+	DefaultExpireSeconds int    `config:"snippets.defaultexpireseconds" json:"defaultexpireseconds" yaml:"defaultexpireseconds"`
+	MaxExpireSeconds     int    `config:"snippets.maxexpireseconds" json:"maxexpireseconds" yaml:"maxexpireseconds"`
+}
+
+type Webhooks struct {
+	AllowedHosts   string `config:"webhooks.allowedhosts" json:"allowedhosts" yaml:"allowedhosts"`
+	Secret         string `config:"webhooks.secret" json:"secret" yaml:"secret"`
+	MaxRetries     int    `config:"webhooks.maxretries" json:"maxretries" yaml:"maxretries"`
+	BackoffMS      int    `config:"webhooks.backoffms" json:"backoffms" yaml:"backoffms"`
+	TimeoutSeconds int    `config:"webhooks.timeoutseconds" json:"timeoutseconds" yaml:"timeoutseconds"`
+}
+
+type RateLimiting struct {
+	Limits            string `config:"ratelimit.limits" json:"limits" yaml:"limits"`
+	DailyExecutions   int    `config:"ratelimit.dailyexecutions" json:"dailyexecutions" yaml:"dailyexecutions"`
+	MonthlyExecutions int    `config:"ratelimit.monthlyexecutions" json:"monthlyexecutions" yaml:"monthlyexecutions"`
+	DailyCPUSeconds   int    `config:"ratelimit.dailycpuseconds" json:"dailycpuseconds" yaml:"dailycpuseconds"`
+	MonthlyCPUSeconds int    `config:"ratelimit.monthlycpuseconds" json:"monthlycpuseconds" yaml:"monthlycpuseconds"`
+}
+
+type Auth struct {
+	KeysPath              string `config:"auth.keyspath" json:"keyspath" yaml:"keyspath"`
+	AllowUnauthenticated  bool   `config:"auth.allowunauthenticated" json:"allowunauthenticated" yaml:"allowunauthenticated"`
+	UnauthenticatedScopes string `config:"auth.unauthenticatedscopes" json:"unauthenticatedscopes" yaml:"unauthenticatedscopes"`
+}
+
+type History struct {
+	Enabled        bool   `config:"history.enabled" json:"enabled" yaml:"enabled"`
+	Store          string `config:"history.store" json:"store" yaml:"store"`
+	Path           string `config:"history.path" json:"path" yaml:"path"`
+	RetentionHours int    `config:"history.retentionhours" json:"retentionhours" yaml:"retentionhours"`
+}
+
+type Metrics struct {
+	BindAddress string `config:"metrics.bindaddress" json:"bindaddress" yaml:"bindaddress"`
+}
+
+type Tracing struct {
+	Exporter    string `config:"tracing.exporter" json:"exporter" yaml:"exporter"`
+	Endpoint    string `config:"tracing.endpoint" json:"endpoint" yaml:"endpoint"`
+	ServiceName string `config:"tracing.servicename" json:"servicename" yaml:"servicename"`
+}
+
+type Health struct {
+	MaxPrepareFailures int `config:"health.maxpreparefailures" json:"maxpreparefailures" yaml:"maxpreparefailures"`
 }
 
 type Scheduler struct {
-	UpdateImages string `config:"scheduler.updateimages" json:"updateimages" yaml:"updateimages"`
-	UpdateSpecs  string `config:"scheduler.updatespecs" json:"updatespecs" yaml:"updatespecs"`
+	UpdateImages   string `config:"scheduler.updateimages" json:"updateimages" yaml:"updateimages"`
+	UpdateSpecs    string `config:"scheduler.updatespecs" json:"updatespecs" yaml:"updatespecs"`
+	CleanupHistory string `config:"scheduler.cleanuphistory" json:"cleanuphistory" yaml:"cleanuphistory"`
 }
 
 type Config struct {
@@ -43,10 +148,19 @@ type Config struct {
 	HostRootDir     string `config:"hostrootdir" json:"hostrootdir" yaml:"hostrootdir"`
 	SkipStartupPrep bool   `config:"skipstartupprep" json:"skipstartupprep" yaml:"skipstartupprep"`
 
-	Log       Log       `json:"log" yaml:"log"`
-	API       API       `json:"api" yaml:"api"`
-	Sandbox   Sandbox   `json:"sandbox" yaml:"sandbox"`
-	Scheduler Scheduler `json:"scheduler" yaml:"scheduler"`
+	Log       Log          `json:"log" yaml:"log"`
+	API       API          `json:"api" yaml:"api"`
+	Sandbox   Sandbox      `json:"sandbox" yaml:"sandbox"`
+	Jobs      Jobs         `json:"jobs" yaml:"jobs"`
+	Snippets  Snippets     `json:"snippets" yaml:"snippets"`
+	History   History      `json:"history" yaml:"history"`
+	Webhooks  Webhooks     `json:"webhooks" yaml:"webhooks"`
+	Auth      Auth         `json:"auth" yaml:"auth"`
+	RateLimit RateLimiting `json:"ratelimit" yaml:"ratelimit"`
+	Metrics   Metrics      `json:"metrics" yaml:"metrics"`
+	Tracing   Tracing      `json:"tracing" yaml:"tracing"`
+	Health    Health       `json:"health" yaml:"health"`
+	Scheduler Scheduler    `json:"scheduler" yaml:"scheduler"`
 }
 
 var defaults = Config{
@@ -68,16 +182,100 @@ var defaults = Config{
 				LimitSeconds: 0,
 			},
 		},
+		GRPC: GRPC{
+			BindAddress: "",
+		},
 	},
 	Sandbox: Sandbox{
-		Runtime:          "",
-		Memory:           "100M",
-		TimeoutSeconds:   20,
+		Runtime:           "",
+		Memory:            "100M",
+		MaxMemory:         "",
+		TimeoutSeconds:    20,
+		MaxTimeoutSeconds: 0,
+		CPUs:              0,
+		MaxCPUs:           0,
+		CPUShares:         0,
+		CPUSetCPUs:        "",
+		PidsLimit:         0,
+		Ulimits: Ulimits{
+			NoFile: 0,
+			FSize:  "",
+			NProc:  0,
+		},
+		Hardening: Hardening{
+			ReadOnlyRootFS:   false,
+			TmpfsSize:        "64M",
+			DropCapabilities: false,
+			NoNewPrivileges:  true,
+			SeccompProfile:   "",
+			UID:              0,
+			GID:              0,
+		},
+		Artifacts: Artifacts{
+			MaxCount: 10,
+			MaxSize:  "10M",
+		},
+		Queue: Queue{
+			MaxConcurrent:  0,
+			MaxLength:      0,
+			TimeoutSeconds: 0,
+		},
+		Cache: Cache{
+			TTLSeconds: 3600,
+			MaxSize:    "50M",
+		},
 		StreamBufferCap:  "50M",
 		EnableNetworking: false,
 	},
+	Jobs: Jobs{
+		ResultTTLSeconds: 3600,
+	},
+	Snippets: Snippets{
+		Store:                "memory",
+		Path:                 "snippets",
+		MaxSize:              "64K",
+		DefaultExpireSeconds: 0,
+		MaxExpireSeconds:     0,
+	},
+	History: History{
+		Enabled:        false,
+		Store:          "memory",
+		Path:           "history.db",
+		RetentionHours: 168,
+	},
+	Webhooks: Webhooks{
+		AllowedHosts:   "",
+		Secret:         "",
+		MaxRetries:     5,
+		BackoffMS:      1000,
+		TimeoutSeconds: 10,
+	},
+	Auth: Auth{
+		KeysPath:              "",
+		AllowUnauthenticated:  true,
+		UnauthenticatedScopes: "exec spec info",
+	},
+	RateLimit: RateLimiting{
+		Limits:            "",
+		DailyExecutions:   0,
+		MonthlyExecutions: 0,
+		DailyCPUSeconds:   0,
+		MonthlyCPUSeconds: 0,
+	},
+	Metrics: Metrics{
+		BindAddress: "",
+	},
+	Tracing: Tracing{
+		Exporter:    "",
+		Endpoint:    "",
+		ServiceName: "ranna",
+	},
+	Health: Health{
+		MaxPrepareFailures: 0,
+	},
 	Scheduler: Scheduler{
-		UpdateImages: "0 3 * * *",
-		UpdateSpecs:  "",
+		UpdateImages:   "0 3 * * *",
+		UpdateSpecs:    "",
+		CleanupHistory: "0 * * * *",
 	},
 }
@@ -1,5 +1,7 @@
 package file
 
+import "io/fs"
+
 type DummyFileProvider struct{}
 
 func NewDummyFileProvider() *DummyFileProvider {
@@ -14,6 +16,14 @@ func (t *DummyFileProvider) CreateFileWithContent(path, content string) error {
 	return nil
 }
 
+func (t *DummyFileProvider) ChownDirectory(path string, uid, gid int) error {
+	return nil
+}
+
 func (t *DummyFileProvider) DeleteDirectory(path string) error {
 	return nil
 }
+
+func (t *DummyFileProvider) OpenDirectory(path string, fn func(fsys fs.FS) error) error {
+	return nil
+}
@@ -1,6 +1,10 @@
 package file
 
-import "os"
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
 
 type LocalFileProvider struct{}
 
@@ -23,6 +27,28 @@ func (t *LocalFileProvider) CreateFileWithContent(path, content string) (err err
 	return err
 }
 
+func (t *LocalFileProvider) ChownDirectory(path string, uid, gid int) error {
+	return filepath.WalkDir(path, func(p string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(p, uid, gid)
+	})
+}
+
 func (t *LocalFileProvider) DeleteDirectory(path string) error {
 	return os.RemoveAll(path)
 }
+
+// OpenDirectory calls fn with a file system of the
+// directory at path. Files can not be accessed outside
+// of the directory, even when following symlinks.
+func (t *LocalFileProvider) OpenDirectory(path string, fn func(fsys fs.FS) error) error {
+	root, err := os.OpenRoot(path)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	return fn(root.FS())
+}
@@ -0,0 +1,134 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+
+	"github.com/zekrotja/rogu/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+	"github.com/ranna-go/ranna/pkg/rannapb"
+)
+
+var (
+	errMissingScope       = status.Error(codes.PermissionDenied, "missing scope")
+	errLanguageNotAllowed = status.Error(codes.PermissionDenied, "language is not allowed")
+)
+
+// methodScopes maps the RPCs to the
+// scopes required to call them.
+var methodScopes = map[string]auth.Scope{
+	rannapb.Ranna_Exec_FullMethodName:        auth.ScopeExec,
+	rannapb.Ranna_ExecStream_FullMethodName:  auth.ScopeExec,
+	rannapb.Ranna_Interactive_FullMethodName: auth.ScopeExec,
+	rannapb.Ranna_Kill_FullMethodName:        auth.ScopeExec,
+	rannapb.Ranna_GetSpec_FullMethodName:     auth.ScopeSpec,
+	rannapb.Ranna_GetInfo_FullMethodName:     auth.ScopeInfo,
+}
+
+// authenticate authenticates the call by its authorization
+// metadata and returns a copy of ctx carrying the identity.
+func (t *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) != 0 {
+			header = v[0]
+		}
+	}
+
+	addr := peerAddr(ctx)
+	id, err := t.auth.Authenticate(header, addr)
+	if err != nil {
+		log.Debug().Err(err).Field("addr", addr).Field("method", method).Msg("gRPC: authentication failed")
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok || !id.HasScope(scope) {
+		return nil, errMissingScope
+	}
+
+	return auth.WithIdentity(ctx, id), nil
+}
+
+func (t *Server) unaryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := t.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.rateLimit(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (t *Server) streamInterceptor(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := t.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if err = t.rateLimit(ctx, info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+}
+
+// identityStream overrides the context of a server
+// stream with the context carrying the identity.
+type identityStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (t *identityStream) Context() context.Context {
+	return t.ctx
+}
+
+// checkLanguage returns an error if the identity passed
+// with ctx is not allowed to execute the language of req.
+func (t *Server) checkLanguage(ctx context.Context, req *models.ExecutionRequest) error {
+	if id := auth.FromContext(ctx); id == nil || !id.CanExec(t.spec.Spec(), req.Language) {
+		return errLanguageNotAllowed
+	}
+	return nil
+}
+
+// withClient passes the identity of the call
+// as client identity with ctx.
+func withClient(ctx context.Context) context.Context {
+	if id := auth.FromContext(ctx); id != nil {
+		return sandbox.WithClient(ctx, id.Client())
+	}
+	return sandbox.WithClient(ctx, peerAddr(ctx))
+}
+
+// peerAddr returns the host of the peer address.
+func peerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,114 @@
+package grpcapi
+
+import (
+	"github.com/ranna-go/ranna/pkg/models"
+	"github.com/ranna-go/ranna/pkg/rannapb"
+)
+
+func requestFromProto(req *rannapb.ExecutionRequest) *models.ExecutionRequest {
+	return &models.ExecutionRequest{
+		Language:         req.GetLanguage(),
+		Code:             req.GetCode(),
+		InlineExpression: req.GetInlineExpression(),
+		Arguments:        req.GetArguments(),
+		Environment:      req.GetEnvironment(),
+		Files:            req.GetFiles(),
+		Stdin:            req.GetStdin(),
+		Memory:           req.GetMemory(),
+		TimeoutSeconds:   int(req.GetTimeoutSeconds()),
+		CPUs:             req.GetCpus(),
+		Artifacts:        req.GetArtifacts(),
+		LiveStats:        req.GetLiveStats(),
+		CallbackURL:      req.GetCallbackUrl(),
+	}
+}
+
+func responseToProto(res *models.ExecutionResponse) *rannapb.ExecutionResponse {
+	return &rannapb.ExecutionResponse{
+		Stdout:             res.StdOut,
+		Stderr:             res.StdErr,
+		ExecTimeMs:         int64(res.ExecTimeMS),
+		ExitCode:           int32(res.ExitCode),
+		TerminationReason:  string(res.TerminationReason),
+		Build:              buildToProto(res.BuildResponse),
+		Artifacts:          artifactsToProto(res.Artifacts),
+		ArtifactsTruncated: res.ArtifactsTruncated,
+		Usage:              usageToProto(res.Usage),
+		Cached:             res.Cached,
+	}
+}
+
+func buildToProto(build *models.BuildResponse) *rannapb.BuildResponse {
+	if build == nil {
+		return nil
+	}
+	return &rannapb.BuildResponse{
+		Stdout:            build.BuildStdOut,
+		Stderr:            build.BuildStdErr,
+		ExitCode:          int32(build.BuildExitCode),
+		TimeMs:            int64(build.BuildTimeMS),
+		TerminationReason: string(build.BuildTerminationReason),
+	}
+}
+
+func artifactToProto(artifact models.Artifact) *rannapb.Artifact {
+	return &rannapb.Artifact{
+		Name:     artifact.Name,
+		MimeType: artifact.MimeType,
+		Size:     artifact.Size,
+		Data:     artifact.Data,
+	}
+}
+
+func artifactsToProto(artifacts []models.Artifact) []*rannapb.Artifact {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	res := make([]*rannapb.Artifact, len(artifacts))
+	for i, artifact := range artifacts {
+		res[i] = artifactToProto(artifact)
+	}
+	return res
+}
+
+func usageToProto(usage *models.ResourceUsage) *rannapb.ResourceUsage {
+	if usage == nil {
+		return nil
+	}
+	return &rannapb.ResourceUsage{
+		PeakMemoryBytes: usage.PeakMemoryBytes,
+		CpuTimeMs:       usage.CPUTimeMS,
+		BlkioReadBytes:  usage.BlockIOReadBytes,
+		BlkioWriteBytes: usage.BlockIOWriteBytes,
+		PeakPids:        usage.PeakPids,
+	}
+}
+
+func phaseToProto(phase *models.PhaseSpec) *rannapb.PhaseSpec {
+	if phase == nil {
+		return nil
+	}
+	return &rannapb.PhaseSpec{
+		Cmd:            phase.Cmd,
+		TimeoutSeconds: int32(phase.TimeoutSeconds),
+	}
+}
+
+func specToProto(spc *models.Spec) *rannapb.Spec {
+	return &rannapb.Spec{
+		Image:          spc.Image,
+		Entrypoint:     spc.Entrypoint,
+		Filename:       spc.FileName,
+		Cmd:            spc.Cmd,
+		Registry:       spc.Registry,
+		Use:            spc.Use,
+		Language:       spc.Language,
+		Example:        spc.Example,
+		SupportsInline: spc.SupportsTemplating(),
+		PoolSize:       int32(spc.PoolSize),
+		MaxConcurrent:  int32(spc.MaxConcurrent),
+		Build:          phaseToProto(spc.Build),
+		Run:            phaseToProto(spc.Run),
+		Cacheable:      spc.Cacheable,
+	}
+}
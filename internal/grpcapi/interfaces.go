@@ -0,0 +1,42 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/ratelimit"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type SpecProvider interface {
+	Spec() *spec.SafeSpecMap
+}
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+type SandboxManager interface {
+	RunInSandbox(
+		ctx context.Context,
+		req *models.ExecutionRequest,
+		chans sandbox.RunChannels,
+	) (res *sandbox.RunResult, err error)
+	KillAndCleanUp(ctx context.Context, id string) (bool, error)
+	WriteStdin(id string, p []byte) (bool, error)
+	CloseStdin(id string) (bool, error)
+	GetProvider() sandbox.Provider
+	PoolInfo() map[string]models.PoolInfo
+}
+
+type Authenticator interface {
+	Authenticate(header, addr string) (*auth.Identity, error)
+}
+
+type RateLimiter interface {
+	Allow(id *auth.Identity, name string) ratelimit.Result
+	ReserveExecution(id *auth.Identity) ratelimit.Result
+}
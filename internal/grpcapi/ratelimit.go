@@ -0,0 +1,51 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/ratelimit"
+	"github.com/ranna-go/ranna/pkg/rannapb"
+)
+
+// methodLimits maps the RPCs to the names
+// of the rate limits they are taken from.
+var methodLimits = map[string]string{
+	rannapb.Ranna_Exec_FullMethodName:        ratelimit.LimitExec,
+	rannapb.Ranna_ExecStream_FullMethodName:  ratelimit.LimitExec,
+	rannapb.Ranna_Interactive_FullMethodName: ratelimit.LimitExec,
+	rannapb.Ranna_Kill_FullMethodName:        "kill",
+	rannapb.Ranna_GetSpec_FullMethodName:     "spec",
+	rannapb.Ranna_GetInfo_FullMethodName:     "info",
+}
+
+// rateLimit takes the call from the rate limit of
+// the method for the identity passed with ctx.
+func (t *Server) rateLimit(ctx context.Context, method string) error {
+	if res := t.limiter.Allow(auth.FromContext(ctx), methodLimits[method]); !res.Allowed {
+		return rejected(res)
+	}
+	return nil
+}
+
+// reserveExecution counts an execution of the identity
+// passed with ctx and returns an error if the execution
+// exceeds its quotas.
+func (t *Server) reserveExecution(ctx context.Context) error {
+	if res := t.limiter.ReserveExecution(auth.FromContext(ctx)); !res.Allowed {
+		return rejected(res)
+	}
+	return nil
+}
+
+func rejected(res ratelimit.Result) error {
+	retryAfter := time.Duration(math.Ceil(res.RetryAfter.Seconds())) * time.Second
+	return status.Error(codes.ResourceExhausted,
+		fmt.Sprintf("%s (retry after %s)", res.Err.Error(), max(retryAfter, time.Second)))
+}
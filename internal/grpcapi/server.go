@@ -0,0 +1,258 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime"
+
+	"github.com/zekrotja/rogu/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/static"
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/rannapb"
+)
+
+var (
+	errOutputLenExceeded = status.Error(codes.ResourceExhausted, "output len exceeded")
+	errEmptyCode         = status.Error(codes.InvalidArgument, "code is empty")
+	errMissingExec       = status.Error(codes.InvalidArgument, "first message must be an execution request")
+	errUnexpectedExec    = status.Error(codes.InvalidArgument, "execution has already been started")
+)
+
+// Server implements the ranna gRPC API on top
+// of a SandboxManager.
+type Server struct {
+	rannapb.UnimplementedRannaServer
+
+	cfg     ConfigProvider
+	spec    SpecProvider
+	manager SandboxManager
+	auth    Authenticator
+	limiter RateLimiter
+
+	bindAddress     string
+	streamBufferCap int
+	server          *grpc.Server
+}
+
+// NewServer returns a new instance of Server.
+func NewServer(
+	cfg ConfigProvider,
+	spec SpecProvider,
+	manager SandboxManager,
+	authenticator Authenticator,
+	limiter RateLimiter,
+) (t *Server, err error) {
+	sbc, err := util.ParseMemoryStr(cfg.Config().Sandbox.StreamBufferCap)
+	if err != nil {
+		return nil, err
+	}
+
+	t = &Server{
+		cfg:             cfg,
+		spec:            spec,
+		manager:         manager,
+		auth:            authenticator,
+		limiter:         limiter,
+		bindAddress:     cfg.Config().API.GRPC.BindAddress,
+		streamBufferCap: int(sbc),
+	}
+	t.server = grpc.NewServer(
+		grpc.UnaryInterceptor(t.unaryInterceptor),
+		grpc.StreamInterceptor(t.streamInterceptor))
+	rannapb.RegisterRannaServer(t.server, t)
+
+	return t, nil
+}
+
+func (t *Server) ListenAndServeBlocking() error {
+	log.Info().Field("addr", t.bindAddress).Msg("Starting gRPC API ...")
+	lis, err := net.Listen("tcp", t.bindAddress)
+	if err != nil {
+		return err
+	}
+	return t.Serve(lis)
+}
+
+// Serve accepts connections on lis until
+// the server is stopped.
+func (t *Server) Serve(lis net.Listener) error {
+	return t.server.Serve(lis)
+}
+
+// Stop stops the server gracefully.
+func (t *Server) Stop() {
+	t.server.GracefulStop()
+}
+
+func (t *Server) Exec(ctx context.Context, pbReq *rannapb.ExecutionRequest) (*rannapb.ExecutionResponse, error) {
+	req := requestFromProto(pbReq)
+	if req.Code == "" && len(req.Files) == 0 {
+		return nil, errEmptyCode
+	}
+	if err := t.checkLanguage(ctx, req); err != nil {
+		return nil, err
+	}
+	if err := t.reserveExecution(ctx); err != nil {
+		return nil, err
+	}
+
+	output := sandbox.NewOutputCollector(t.streamBufferCap)
+
+	var runRes *sandbox.RunResult
+	var err error
+	execTime := util.MeasureTime(func() {
+		runRes, err = t.manager.RunInSandbox(withClient(ctx), req, sandbox.RunChannels{
+			Stdout: output.Stdout,
+			Stderr: output.Stderr,
+		})
+	})
+	stdOut, stdErr := output.Close()
+
+	if err != nil {
+		return nil, mapRunError(err)
+	}
+
+	res := runRes.ToModel(stdOut, stdErr, execTime)
+
+	if err = t.checkOutputLen(res.StdOut, res.StdErr); err != nil {
+		return nil, err
+	}
+
+	return responseToProto(res), nil
+}
+
+func (t *Server) ExecStream(pbReq *rannapb.ExecutionRequest, stream grpc.ServerStreamingServer[rannapb.Event]) error {
+	req := requestFromProto(pbReq)
+	if req.Code == "" && len(req.Files) == 0 {
+		return errEmptyCode
+	}
+	if err := t.checkLanguage(stream.Context(), req); err != nil {
+		return err
+	}
+	if err := t.reserveExecution(stream.Context()); err != nil {
+		return err
+	}
+
+	return t.stream(stream.Context(), req, stream.Send, nil)
+}
+
+func (t *Server) Interactive(stream grpc.BidiStreamingServer[rannapb.InteractiveRequest, rannapb.Event]) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if msg.GetExec() == nil {
+		return errMissingExec
+	}
+
+	req := requestFromProto(msg.GetExec())
+	if req.Code == "" && len(req.Files) == 0 {
+		return errEmptyCode
+	}
+	if err = t.checkLanguage(stream.Context(), req); err != nil {
+		return err
+	}
+	if err = t.reserveExecution(stream.Context()); err != nil {
+		return err
+	}
+	req.Interactive = true
+
+	// When forwarding stdin fails, the execution is
+	// canceled and the cause is returned to the client.
+	ctx, cancel := context.WithCancelCause(stream.Context())
+	defer cancel(nil)
+
+	pump := newStdinPump(t.manager)
+	go func() {
+		if err := pump.forward(ctx, stream.Recv); err != nil {
+			cancel(err)
+		}
+	}()
+
+	// Executions canceled after the spawn are finished as
+	// killed, so the cause is returned even without error.
+	err = t.stream(ctx, req, stream.Send, pump.start)
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return err
+}
+
+func (t *Server) Kill(ctx context.Context, req *rannapb.KillRequest) (*rannapb.KillResponse, error) {
+	killed, err := t.manager.KillAndCleanUp(ctx, req.GetRunId())
+	if err != nil {
+		return nil, mapRunError(err)
+	}
+	return &rannapb.KillResponse{Killed: killed}, nil
+}
+
+func (t *Server) GetSpec(context.Context, *rannapb.GetSpecRequest) (*rannapb.GetSpecResponse, error) {
+	snapshot := t.spec.Spec().GetSnapshot()
+	res := &rannapb.GetSpecResponse{
+		Specs: make(map[string]*rannapb.Spec, len(snapshot)),
+	}
+	for key, spc := range snapshot {
+		res.Specs[key] = specToProto(spc)
+	}
+	return res, nil
+}
+
+func (t *Server) GetInfo(ctx context.Context, _ *rannapb.GetInfoRequest) (*rannapb.GetInfoResponse, error) {
+	sandboxInfo, err := t.manager.GetProvider().Info(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &rannapb.GetInfoResponse{
+		Version:   static.Version,
+		BuildDate: static.BuildDate,
+		GoVersion: runtime.Version(),
+		Pool:      make(map[string]*rannapb.PoolInfo),
+	}
+	if sandboxInfo != nil {
+		res.Sandbox = &rannapb.SandboxInfo{
+			Type:    sandboxInfo.Type,
+			Version: sandboxInfo.Version,
+		}
+	}
+	for key, pool := range t.manager.PoolInfo() {
+		res.Pool[key] = &rannapb.PoolInfo{
+			Size:      int32(pool.Size),
+			Available: int32(pool.Available),
+		}
+	}
+
+	return res, nil
+}
+
+func (t *Server) checkOutputLen(stdout, stderr string) (err error) {
+	maxOutLen, err := util.ParseMemoryStr(t.cfg.Config().API.MaxOutputLen)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if int64(len(stdout))+int64(len(stderr)) > maxOutLen {
+		return errOutputLenExceeded
+	}
+
+	return nil
+}
+
+func mapRunError(err error) error {
+	switch {
+	case sandbox.IsSystemError(err):
+		return status.Error(codes.Internal, err.Error())
+	case sandbox.IsUnavailableError(err):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
@@ -0,0 +1,373 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/ratelimit"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+	"github.com/ranna-go/ranna/pkg/rannapb"
+)
+
+type staticConfig struct {
+	c *config.Config
+}
+
+func (t staticConfig) Config() *config.Config { return t.c }
+
+type specMapProvider struct {
+	m *spec.SafeSpecMap
+}
+
+func (t specMapProvider) Spec() *spec.SafeSpecMap { return t.m }
+
+// fakeSandboxManager runs executions which print their
+// code. Interactive executions echo their stdin instead
+// until it is closed.
+type fakeSandboxManager struct {
+	SandboxManager
+
+	running atomic.Bool
+	cStdin  chan []byte
+}
+
+func (t *fakeSandboxManager) RunInSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	chans sandbox.RunChannels,
+) (*sandbox.RunResult, error) {
+	if req.Language == "" {
+		return nil, errors.New("unsupported language spec")
+	}
+	if sandbox.ClientFromContext(ctx) == "" {
+		return nil, errors.New("no client identity")
+	}
+	if chans.Spawn != nil {
+		chans.Spawn <- "run"
+	}
+	t.running.Store(true)
+	defer t.running.Store(false)
+
+	if !req.Interactive {
+		chans.Stdout <- []byte(req.Code)
+		return &sandbox.RunResult{ExitCode: 1}, nil
+	}
+
+	for p := range t.cStdin {
+		chans.Stdout <- p
+	}
+	return &sandbox.RunResult{}, nil
+}
+
+func (t *fakeSandboxManager) WriteStdin(id string, p []byte) (bool, error) {
+	if !t.running.Load() {
+		return false, nil
+	}
+	t.cStdin <- p
+	return true, nil
+}
+
+func (t *fakeSandboxManager) CloseStdin(id string) (bool, error) {
+	if !t.running.Load() {
+		return false, nil
+	}
+	close(t.cStdin)
+	return true, nil
+}
+
+func (t *fakeSandboxManager) KillAndCleanUp(ctx context.Context, id string) (bool, error) {
+	return id == "run", nil
+}
+
+const testKeys = `
+- name: builder
+  key: secret
+  scopes: [exec]
+  languages: [go]
+`
+
+func newTestClient(t *testing.T) rannapb.RannaClient {
+	keysPath := filepath.Join(t.TempDir(), "keys.yaml")
+	err := os.WriteFile(keysPath, []byte(testKeys), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := staticConfig{&config.Config{
+		API:     config.API{MaxOutputLen: "1K"},
+		Sandbox: config.Sandbox{StreamBufferCap: "1M"},
+		Auth: config.Auth{
+			KeysPath:              keysPath,
+			AllowUnauthenticated:  true,
+			UnauthenticatedScopes: "exec spec",
+		},
+		RateLimit: config.RateLimiting{Limits: "spec:2/1m"},
+	}}
+	specs := specMapProvider{spec.NewSafeSpecMap(models.SpecMap{
+		"python": {Image: "python", FileName: "main.py", Cacheable: true},
+		"go":     {Image: "golang", FileName: "main.go", Build: &models.PhaseSpec{Cmd: "go build"}},
+	})}
+	mgr := &fakeSandboxManager{cStdin: make(chan []byte)}
+
+	authenticator, err := auth.NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limiter, err := ratelimit.NewManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(cfg, specs, mgr, authenticator, limiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return rannapb.NewRannaClient(conn)
+}
+
+// recvAll receives all events from the stream and
+// returns them with the error the stream ended with.
+func recvAll(recv func() (*rannapb.Event, error)) (events []*rannapb.Event, err error) {
+	for {
+		evt, err := recv()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, evt)
+	}
+}
+
+func TestExec(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	res, err := client.Exec(ctx, &rannapb.ExecutionRequest{Language: "python", Code: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetStdout() != "hello" || res.GetExitCode() != 1 || res.GetTerminationReason() != "exited" {
+		t.Errorf("invalid response: %v", res)
+	}
+
+	expectCode := func(req *rannapb.ExecutionRequest, code codes.Code) {
+		t.Helper()
+		_, err := client.Exec(ctx, req)
+		if c := status.Code(err); c != code {
+			t.Errorf("invalid status code: %s (expected: %s)", c, code)
+		}
+	}
+
+	expectCode(&rannapb.ExecutionRequest{Language: "python"}, codes.InvalidArgument)
+	expectCode(&rannapb.ExecutionRequest{Code: "hello"}, codes.InvalidArgument)
+	expectCode(&rannapb.ExecutionRequest{Language: "python", Code: string(make([]byte, 2048))},
+		codes.ResourceExhausted)
+}
+
+func TestExecStream(t *testing.T) {
+	client := newTestClient(t)
+
+	stream, err := client.ExecStream(context.Background(),
+		&rannapb.ExecutionRequest{Language: "python", Code: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := recvAll(stream.Recv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("invalid number of events: %d", len(events))
+	}
+	if events[0].GetSpawn() == nil || events[0].GetRunId() != "run" {
+		t.Errorf("invalid spawn event: %v", events[0])
+	}
+	if string(events[1].GetLog().GetStdout()) != "hello" {
+		t.Errorf("invalid log event: %v", events[1])
+	}
+	if stop := events[2].GetStop(); stop == nil || stop.GetExitCode() != 1 {
+		t.Errorf("invalid stop event: %v", events[2])
+	}
+
+	stream, err = client.ExecStream(context.Background(), &rannapb.ExecutionRequest{Code: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = recvAll(stream.Recv); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid error: %v", err)
+	}
+}
+
+func TestInteractive(t *testing.T) {
+	client := newTestClient(t)
+
+	stream, err := client.Interactive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(req *rannapb.InteractiveRequest) {
+		t.Helper()
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stdin := func(data string, close bool) *rannapb.InteractiveRequest {
+		return &rannapb.InteractiveRequest{Request: &rannapb.InteractiveRequest_Stdin_{
+			Stdin: &rannapb.InteractiveRequest_Stdin{Data: []byte(data), Close: close},
+		}}
+	}
+
+	// Stdin sent right after the execution request must
+	// be held back until the sandbox has been spawned.
+	send(&rannapb.InteractiveRequest{Request: &rannapb.InteractiveRequest_Exec{
+		Exec: &rannapb.ExecutionRequest{Language: "python", Code: "input()"},
+	}})
+	send(stdin("hello", false))
+	send(stdin("world", true))
+	stream.CloseSend()
+
+	events, err := recvAll(stream.Recv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout string
+	for _, evt := range events {
+		stdout += string(evt.GetLog().GetStdout())
+	}
+	if stdout != "helloworld" {
+		t.Errorf("invalid stdout: %q", stdout)
+	}
+	if events[len(events)-1].GetStop() == nil {
+		t.Errorf("last event is not stop: %v", events[len(events)-1])
+	}
+
+	stream, err = client.Interactive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	send(stdin("hello", false))
+	if _, err = recvAll(stream.Recv); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid error: %v", err)
+	}
+}
+
+func TestKill(t *testing.T) {
+	client := newTestClient(t)
+
+	res, err := client.Kill(context.Background(), &rannapb.KillRequest{RunId: "run"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.GetKilled() {
+		t.Error("sandbox has not been killed")
+	}
+
+	if res, err = client.Kill(context.Background(), &rannapb.KillRequest{RunId: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	if res.GetKilled() {
+		t.Error("unknown sandbox has been killed")
+	}
+}
+
+func TestGetSpec(t *testing.T) {
+	client := newTestClient(t)
+
+	res, err := client.GetSpec(context.Background(), &rannapb.GetSpecRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	specs := res.GetSpecs()
+	if len(specs) != 2 {
+		t.Fatalf("invalid number of specs: %d", len(specs))
+	}
+	if s := specs["python"]; s.GetImage() != "python" || s.GetFilename() != "main.py" || !s.GetCacheable() {
+		t.Errorf("invalid python spec: %v", s)
+	}
+	if s := specs["go"]; s.GetBuild().GetCmd() != "go build" {
+		t.Errorf("invalid go spec: %v", s)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	client := newTestClient(t)
+
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+key)
+	}
+	expectCode := func(err error, code codes.Code) {
+		t.Helper()
+		if c := status.Code(err); c != code {
+			t.Errorf("invalid status code: %s (expected: %s)", c, code)
+		}
+	}
+
+	_, err := client.GetInfo(context.Background(), &rannapb.GetInfoRequest{})
+	expectCode(err, codes.PermissionDenied)
+
+	_, err = client.GetSpec(withKey("invalid"), &rannapb.GetSpecRequest{})
+	expectCode(err, codes.Unauthenticated)
+
+	_, err = client.GetSpec(withKey("secret"), &rannapb.GetSpecRequest{})
+	expectCode(err, codes.PermissionDenied)
+
+	_, err = client.Exec(withKey("secret"), &rannapb.ExecutionRequest{Language: "python", Code: "hello"})
+	expectCode(err, codes.PermissionDenied)
+
+	_, err = client.Exec(withKey("secret"), &rannapb.ExecutionRequest{Language: "go", Code: "hello"})
+	expectCode(err, codes.OK)
+}
+
+func TestRateLimit(t *testing.T) {
+	client := newTestClient(t)
+
+	for i := range 3 {
+		_, err := client.GetSpec(context.Background(), &rannapb.GetSpecRequest{})
+		expected := codes.OK
+		if i == 2 {
+			expected = codes.ResourceExhausted
+		}
+		if c := status.Code(err); c != expected {
+			t.Errorf("invalid status code for call %d: %s (expected: %s)", i, c, expected)
+		}
+	}
+}
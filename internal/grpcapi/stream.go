@@ -0,0 +1,251 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/zekrotja/rogu/log"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+	"github.com/ranna-go/ranna/pkg/rannapb"
+)
+
+// stdinRetryInterval is the interval in which writing
+// stdin is retried while the sandbox is not tracked
+// by the manager yet.
+const stdinRetryInterval = 10 * time.Millisecond
+
+// stream runs req and sends its events using send until
+// the execution has finished. If onReady is not nil, it
+// is called with the run ID of the execution as soon as
+// the sandbox of the run phase has been created.
+func (t *Server) stream(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	send func(*rannapb.Event) error,
+	onReady func(runId string),
+) error {
+	// Stdin can only be passed to the sandbox of the
+	// run phase, so the build phase must pass first.
+	_, spc, _ := t.spec.Spec().Resolve(req.Language)
+	hasBuild := spc.Build != nil
+
+	// The spawn channel is unbuffered so that the
+	// spawn event is always sent before any output.
+	cQueued := make(chan int)
+	cSpn := make(chan string)
+	cStdOut := make(chan []byte)
+	cStdErr := make(chan []byte)
+	cPhase := make(chan sandbox.PhaseEvent)
+	cStop := make(chan struct{})
+	cDone := make(chan struct{})
+
+	var cStats chan models.ResourceUsage
+	if req.LiveStats {
+		cStats = make(chan models.ResourceUsage)
+	}
+
+	var runId string
+	var sendErr error
+
+	// emit sends evt unless sending has failed before,
+	// which means that the client has disconnected.
+	emit := func(evt *rannapb.Event) {
+		if sendErr != nil {
+			return
+		}
+		evt.RunId = runId
+		sendErr = send(evt)
+	}
+
+	go func() {
+		defer close(cDone)
+		for {
+			select {
+			case <-cStop:
+				return
+			case pos := <-cQueued:
+				emit(&rannapb.Event{Event: &rannapb.Event_Queued_{
+					Queued: &rannapb.Event_Queued{Position: int32(pos)},
+				}})
+			case runId = <-cSpn:
+				emit(&rannapb.Event{Event: &rannapb.Event_Spawn_{
+					Spawn: &rannapb.Event_Spawn{},
+				}})
+				if onReady != nil && !hasBuild {
+					onReady(runId)
+				}
+			case p := <-cStdOut:
+				emit(&rannapb.Event{Event: &rannapb.Event_Log_{
+					Log: &rannapb.Event_Log{Stdout: p},
+				}})
+			case p := <-cStdErr:
+				emit(&rannapb.Event{Event: &rannapb.Event_Log_{
+					Log: &rannapb.Event_Log{Stderr: p},
+				}})
+			case evt := <-cPhase:
+				emit(phaseEventToProto(evt))
+				if onReady != nil && evt.Phase == sandbox.PhaseRunStart {
+					onReady(runId)
+				}
+			case usage := <-cStats:
+				emit(&rannapb.Event{Event: &rannapb.Event_Stats{
+					Stats: usageToProto(&usage),
+				}})
+			}
+		}
+	}()
+
+	var res *sandbox.RunResult
+	var err error
+	execTime := util.MeasureTime(func() {
+		res, err = t.manager.RunInSandbox(withClient(ctx), req, sandbox.RunChannels{
+			Queued: cQueued,
+			Spawn:  cSpn,
+			Stdout: cStdOut,
+			Stderr: cStdErr,
+			Phase:  cPhase,
+			Stats:  cStats,
+		})
+	})
+	close(cStop)
+	<-cDone
+
+	if err != nil {
+		return mapRunError(err)
+	}
+
+	for _, artifact := range res.Artifacts {
+		emit(&rannapb.Event{Event: &rannapb.Event_Artifact{
+			Artifact: artifactToProto(artifact),
+		}})
+	}
+
+	emit(&rannapb.Event{Event: &rannapb.Event_Stop_{
+		Stop: &rannapb.Event_Stop{
+			ExecTimeMs:         execTime.Milliseconds(),
+			ExitCode:           int32(res.ExitCode),
+			TerminationReason:  string(res.TerminationReason()),
+			ArtifactsTruncated: res.ArtifactsTruncated,
+			Usage:              usageToProto(res.Usage),
+			Cached:             res.Cached,
+		},
+	}})
+
+	return sendErr
+}
+
+func phaseEventToProto(evt sandbox.PhaseEvent) *rannapb.Event {
+	switch evt.Phase {
+	case sandbox.PhaseBuildStart:
+		return &rannapb.Event{Event: &rannapb.Event_BuildStart_{
+			BuildStart: &rannapb.Event_BuildStart{},
+		}}
+	case sandbox.PhaseBuildEnd:
+		var build *models.BuildResponse
+		if evt.Build != nil {
+			build = evt.Build.ToModel()
+		}
+		return &rannapb.Event{Event: &rannapb.Event_BuildEnd_{
+			BuildEnd: &rannapb.Event_BuildEnd{Build: buildToProto(build)},
+		}}
+	default:
+		return &rannapb.Event{Event: &rannapb.Event_RunStart_{
+			RunStart: &rannapb.Event_RunStart{},
+		}}
+	}
+}
+
+// stdinPump forwards the stdin messages of an
+// interactive stream to the sandbox of the execution.
+type stdinPump struct {
+	manager SandboxManager
+	cRunId  chan string
+}
+
+func newStdinPump(manager SandboxManager) *stdinPump {
+	return &stdinPump{
+		manager: manager,
+		cRunId:  make(chan string, 1),
+	}
+}
+
+// start releases the stdin messages received so far
+// to the sandbox with the given run ID.
+func (t *stdinPump) start(runId string) {
+	select {
+	case t.cRunId <- runId:
+	default:
+	}
+}
+
+// forward receives messages using recv and passes them
+// to the sandbox until the stream is closed by the client
+// or ctx is canceled. Messages received before the sandbox
+// is ready are held back until start is called.
+func (t *stdinPump) forward(ctx context.Context, recv func() (*rannapb.InteractiveRequest, error)) error {
+	var runId string
+	for {
+		msg, err := recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		stdin := msg.GetStdin()
+		if stdin == nil {
+			return errUnexpectedExec
+		}
+
+		if runId == "" {
+			select {
+			case runId = <-t.cRunId:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		if len(stdin.GetData()) != 0 {
+			err = t.retry(ctx, func() (bool, error) {
+				return t.manager.WriteStdin(runId, stdin.GetData())
+			})
+			if err != nil {
+				log.Error().Err(err).Field("runid", runId).Msg("gRPC: failed writing stdin")
+			}
+		}
+		if stdin.GetClose() {
+			err = t.retry(ctx, func() (bool, error) {
+				return t.manager.CloseStdin(runId)
+			})
+			if err != nil {
+				log.Error().Err(err).Field("runid", runId).Msg("gRPC: failed closing stdin")
+			}
+		}
+	}
+}
+
+// retry calls fn until it reports that the sandbox has
+// been found or ctx is canceled. The sandbox is tracked
+// by the manager shortly after it has been spawned.
+func (t *stdinPump) retry(ctx context.Context, fn func() (bool, error)) error {
+	ticker := time.NewTicker(stdinRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := fn()
+		if ok || err != nil {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
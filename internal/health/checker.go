@@ -0,0 +1,150 @@
+// Package health provides the readiness
+// checks of a ranna instance.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// Names of the readiness checks.
+const (
+	CheckDocker  = "docker"
+	CheckSpecs   = "specs"
+	CheckPrepare = "prepare"
+	CheckHostDir = "hostdir"
+)
+
+const checkTimeout = 5 * time.Second
+
+var (
+	errNoSpecs   = errors.New("no specs loaded")
+	errPreparing = errors.New("environments are being prepared")
+)
+
+// Checker checks whether the instance is
+// ready to serve executions.
+type Checker struct {
+	cfg     ConfigProvider
+	spec    SpecProvider
+	sandbox SandboxProvider
+
+	mtx         sync.RWMutex
+	prepared    bool
+	prepareErrs []error
+}
+
+// NewChecker returns a new instance of Checker.
+//
+// The instance is not ready until SetPrepared
+// has been called.
+func NewChecker(cfg ConfigProvider, spec SpecProvider, sandbox SandboxProvider) *Checker {
+	return &Checker{
+		cfg:     cfg,
+		spec:    spec,
+		sandbox: sandbox,
+	}
+}
+
+// SetPrepared records that the preparation of the
+// spec environments on startup has finished with
+// the given errors.
+func (t *Checker) SetPrepared(errs []error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.prepared = true
+	t.prepareErrs = errs
+}
+
+// Check runs all readiness checks. The instance
+// is ready if all of them passed.
+func (t *Checker) Check(ctx context.Context) models.Readiness {
+	checks := []struct {
+		name  string
+		check func(context.Context) error
+	}{
+		{CheckDocker, t.checkDocker},
+		{CheckSpecs, t.checkSpecs},
+		{CheckPrepare, t.checkPrepare},
+		{CheckHostDir, t.checkHostDir},
+	}
+
+	res := models.Readiness{
+		Ready:  true,
+		Checks: make([]models.HealthCheck, 0, len(checks)),
+	}
+	for _, c := range checks {
+		hc := models.HealthCheck{Name: c.name, OK: true}
+		if err := c.check(ctx); err != nil {
+			hc.OK = false
+			hc.Error = err.Error()
+			res.Ready = false
+		}
+		res.Checks = append(res.Checks, hc)
+	}
+
+	return res
+}
+
+// checkDocker checks that the sandbox
+// provider is reachable.
+func (t *Checker) checkDocker(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	_, err := t.sandbox.Info(ctx)
+	return err
+}
+
+// checkSpecs checks that at least
+// one spec is loaded.
+func (t *Checker) checkSpecs(context.Context) error {
+	if len(t.spec.Spec().GetSnapshot()) == 0 {
+		return errNoSpecs
+	}
+	return nil
+}
+
+// checkPrepare checks that the preparation on startup
+// has finished and not more environments failed to
+// prepare than tolerated. A negative maximum tolerates
+// any number of failures.
+func (t *Checker) checkPrepare(context.Context) error {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	if !t.prepared {
+		return errPreparing
+	}
+
+	maxFailures := t.cfg.Config().Health.MaxPrepareFailures
+	if maxFailures >= 0 && len(t.prepareErrs) > maxFailures {
+		return fmt.Errorf("%d environments failed to prepare (%d tolerated): %w",
+			len(t.prepareErrs), maxFailures, errors.Join(t.prepareErrs...))
+	}
+
+	return nil
+}
+
+// checkHostDir checks that files can be created
+// in the host root directory.
+func (t *Checker) checkHostDir(context.Context) error {
+	dir := t.cfg.Config().HostRootDir
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
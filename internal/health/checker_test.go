@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type staticConfig struct {
+	c *config.Config
+}
+
+func (t staticConfig) Config() *config.Config { return t.c }
+
+type specMapProvider struct {
+	m *spec.SafeSpecMap
+}
+
+func (t specMapProvider) Spec() *spec.SafeSpecMap { return t.m }
+
+type fakeSandboxProvider struct {
+	err error
+}
+
+func (t fakeSandboxProvider) Info(context.Context) (*models.SandboxInfo, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &models.SandboxInfo{Type: "fake"}, nil
+}
+
+func failed(res models.Readiness) map[string]bool {
+	m := map[string]bool{}
+	for _, c := range res.Checks {
+		if !c.OK {
+			m[c.Name] = true
+		}
+	}
+	return m
+}
+
+func TestCheck(t *testing.T) {
+	cfg := &config.Config{HostRootDir: filepath.Join(t.TempDir(), "host")}
+	specs := specMapProvider{spec.NewSafeSpecMap(models.SpecMap{"python3": {Image: "python:3"}})}
+	c := NewChecker(staticConfig{cfg}, specs, fakeSandboxProvider{})
+
+	res := c.Check(context.Background())
+	if res.Ready {
+		t.Fatal("expected not to be ready before preparation")
+	}
+	if f := failed(res); len(f) != 1 || !f[CheckPrepare] {
+		t.Fatalf("expected only prepare check to fail, got %v", f)
+	}
+
+	c.SetPrepared(nil)
+	if res = c.Check(context.Background()); !res.Ready {
+		t.Fatalf("expected to be ready, got %+v", res)
+	}
+	if len(res.Checks) != 4 {
+		t.Fatalf("expected 4 checks, got %d", len(res.Checks))
+	}
+	if _, err := os.Stat(cfg.HostRootDir); err != nil {
+		t.Fatalf("expected host root dir to be created: %v", err)
+	}
+}
+
+func TestCheckFailures(t *testing.T) {
+	cfg := &config.Config{HostRootDir: t.TempDir()}
+	specs := specMapProvider{spec.NewSafeSpecMap(models.SpecMap{})}
+	c := NewChecker(staticConfig{cfg}, specs, fakeSandboxProvider{errors.New("connection refused")})
+	c.SetPrepared(nil)
+
+	f := failed(c.Check(context.Background()))
+	if len(f) != 2 || !f[CheckDocker] || !f[CheckSpecs] {
+		t.Fatalf("expected docker and specs checks to fail, got %v", f)
+	}
+}
+
+func TestCheckPrepareFailures(t *testing.T) {
+	cfg := &config.Config{HostRootDir: t.TempDir()}
+	specs := specMapProvider{spec.NewSafeSpecMap(models.SpecMap{"python3": {Image: "python:3"}})}
+	c := NewChecker(staticConfig{cfg}, specs, fakeSandboxProvider{})
+	c.SetPrepared([]error{errors.New("pull failed"), errors.New("pull failed")})
+
+	for _, tc := range []struct {
+		max   int
+		ready bool
+	}{
+		{0, false},
+		{1, false},
+		{2, true},
+		{-1, true},
+	} {
+		cfg.Health.MaxPrepareFailures = tc.max
+		if res := c.Check(context.Background()); res.Ready != tc.ready {
+			t.Errorf("max %d: expected ready %t, got %+v", tc.max, tc.ready, res)
+		}
+	}
+}
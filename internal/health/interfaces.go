@@ -0,0 +1,21 @@
+package health
+
+import (
+	"context"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+type SpecProvider interface {
+	Spec() *spec.SafeSpecMap
+}
+
+type SandboxProvider interface {
+	Info(ctx context.Context) (*models.SandboxInfo, error)
+}
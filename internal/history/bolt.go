@@ -0,0 +1,109 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+var bucketExecutions = []byte("executions")
+
+// BoltStore implements Store using an
+// embedded bbolt database file.
+//
+// Records are keyed by their IDs, which are
+// expected to be sortable by creation time.
+type BoltStore struct {
+	db *bbolt.DB
+}
+
+// NewBoltStore opens or creates the database
+// file at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketExecutions)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (t *BoltStore) Put(e *models.Execution) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketExecutions).Put([]byte(e.ID), data)
+	})
+}
+
+func (t *BoltStore) Get(id string) (e *models.Execution, ok bool, err error) {
+	err = t.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(bucketExecutions).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(data, &e)
+	})
+	return e, ok, err
+}
+
+func (t *BoltStore) List(filter Filter) ([]*models.Execution, error) {
+	p := paginator{filter: filter}
+
+	err := t.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(bucketExecutions).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var e models.Execution
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if filter.Matches(&e) && !p.add(&e) {
+				break
+			}
+		}
+		return nil
+	})
+
+	return p.res, err
+}
+
+func (t *BoltStore) DeleteBefore(before time.Time) (n int, err error) {
+	err = t.db.Update(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(bucketExecutions).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var e models.Execution
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if !e.Created.Before(before) {
+				continue
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+func (t *BoltStore) Close() error {
+	return t.db.Close()
+}
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// Filter specifies the execution records
+// returned from Store.List. Empty fields
+// are not used for filtering.
+//
+// Offset and Limit are applied after
+// filtering. A Limit of 0 means no limit.
+type Filter struct {
+	Language          string
+	Client            string
+	TerminationReason models.TerminationReason
+	Since             time.Time
+	Until             time.Time
+
+	Offset int
+	Limit  int
+}
+
+// Matches returns true if e matches
+// the filter's criteria.
+func (t Filter) Matches(e *models.Execution) bool {
+	if t.Language != "" && e.Language != t.Language {
+		return false
+	}
+	if t.Client != "" && e.Client != t.Client {
+		return false
+	}
+	if t.TerminationReason != "" &&
+		(e.Response == nil || e.Response.TerminationReason != t.TerminationReason) {
+		return false
+	}
+	if !t.Since.IsZero() && e.Created.Before(t.Since) {
+		return false
+	}
+	if !t.Until.IsZero() && !e.Created.Before(t.Until) {
+		return false
+	}
+	return true
+}
+
+// paginator applies the offset and limit of
+// a filter to a sequence of matching records.
+type paginator struct {
+	filter  Filter
+	skipped int
+	res     []*models.Execution
+}
+
+// add adds e to the result if it is within the
+// requested page and returns false when the page
+// is full.
+func (t *paginator) add(e *models.Execution) bool {
+	if t.skipped < t.filter.Offset {
+		t.skipped++
+		return true
+	}
+	t.res = append(t.res, e)
+	return t.filter.Limit <= 0 || len(t.res) < t.filter.Limit
+}
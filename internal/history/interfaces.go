@@ -0,0 +1,37 @@
+package history
+
+import (
+	"time"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+// Store defines an interface to persist and
+// query execution records.
+type Store interface {
+
+	// Put stores the given execution record.
+	Put(e *models.Execution) error
+
+	// Get returns the execution record with the
+	// given ID. If no record has been found, ok
+	// is false.
+	Get(id string) (e *models.Execution, ok bool, err error)
+
+	// List returns all execution records matching
+	// the given filter, ordered from newest to oldest.
+	List(filter Filter) ([]*models.Execution, error)
+
+	// DeleteBefore deletes all execution records
+	// created before t and returns the number of
+	// deleted records.
+	DeleteBefore(t time.Time) (n int, err error)
+
+	// Close releases the resources of the store.
+	Close() error
+}
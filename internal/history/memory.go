@@ -0,0 +1,79 @@
+package history
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// MemoryStore implements Store by keeping
+// execution records in memory.
+type MemoryStore struct {
+	mtx        sync.RWMutex
+	executions map[string]*models.Execution
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		executions: make(map[string]*models.Execution),
+	}
+}
+
+func (t *MemoryStore) Put(e *models.Execution) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.executions[e.ID] = e
+	return nil
+}
+
+func (t *MemoryStore) Get(id string) (e *models.Execution, ok bool, err error) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	e, ok = t.executions[id]
+	return e, ok, nil
+}
+
+func (t *MemoryStore) List(filter Filter) ([]*models.Execution, error) {
+	t.mtx.RLock()
+	matches := make([]*models.Execution, 0, len(t.executions))
+	for _, e := range t.executions {
+		if filter.Matches(e) {
+			matches = append(matches, e)
+		}
+	}
+	t.mtx.RUnlock()
+
+	slices.SortFunc(matches, func(a, b *models.Execution) int {
+		return b.Created.Compare(a.Created)
+	})
+
+	p := paginator{filter: filter}
+	for _, e := range matches {
+		if !p.add(e) {
+			break
+		}
+	}
+
+	return p.res, nil
+}
+
+func (t *MemoryStore) DeleteBefore(before time.Time) (n int, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for id, e := range t.executions {
+		if e.Created.Before(before) {
+			delete(t.executions, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (t *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/log"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// Recorder wraps a sandbox manager and records
+// every execution run through it into a Store.
+type Recorder struct {
+	*sandbox.Manager
+
+	store  Store
+	cfg    ConfigProvider
+	logger rogu.Logger
+}
+
+// NewRecorder returns a new instance of Recorder
+// recording executions of manager into store.
+func NewRecorder(manager *sandbox.Manager, store Store, cfg ConfigProvider) *Recorder {
+	return &Recorder{
+		Manager: manager,
+		store:   store,
+		cfg:     cfg,
+		logger:  log.Tagged("History"),
+	}
+}
+
+// RunInSandbox runs the given request like
+// sandbox.Manager.RunInSandbox and records the
+// execution afterwards.
+func (t *Recorder) RunInSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	chans sandbox.RunChannels,
+) (res *sandbox.RunResult, err error) {
+	bufferCap, err := util.ParseMemoryStr(t.cfg.Config().Sandbox.StreamBufferCap)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &models.Execution{
+		ID:       xid.New().String(),
+		Language: req.Language,
+		Client:   sandbox.ClientFromContext(ctx),
+		Created:  time.Now(),
+		Request:  req,
+	}
+
+	// The output is passed through to the channels
+	// of the caller and collected for the record.
+	tee, teeChans := sandbox.NewOutputTee(chans, int(bufferCap))
+	execTime := util.MeasureTime(func() {
+		res, err = t.Manager.RunInSandbox(ctx, req, teeChans)
+	})
+	stdOut, stdErr := tee.Close()
+
+	if err != nil {
+		e.Error = sandbox.ErrorModel(err)
+	} else {
+		e.Response = res.ToModel(stdOut, stdErr, execTime)
+		e.Response.Artifacts = stripArtifacts(e.Response.Artifacts)
+	}
+
+	if pErr := t.store.Put(e); pErr != nil {
+		t.logger.Error().Err(pErr).Field("id", e.ID).Msg("failed storing execution")
+	}
+
+	return res, err
+}
+
+// stripArtifacts returns a copy of artifacts
+// without their contents, which are not kept
+// in the history.
+func stripArtifacts(artifacts []models.Artifact) []models.Artifact {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	stripped := make([]models.Artifact, len(artifacts))
+	for i, a := range artifacts {
+		a.Data = nil
+		stripped[i] = a
+	}
+	return stripped
+}
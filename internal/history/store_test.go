@@ -0,0 +1,95 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	languages := []string{"go", "python", "go", "go", "python"}
+	ids := make([]string, len(languages))
+	for i, lang := range languages {
+		created := start.Add(time.Duration(i) * time.Minute)
+		e := &models.Execution{
+			ID:       xid.NewWithTime(created).String(),
+			Language: lang,
+			Client:   "client",
+			Created:  created,
+			Request:  &models.ExecutionRequest{Language: lang},
+			Response: &models.ExecutionResponse{TerminationReason: models.TerminationExited},
+		}
+		if i == 3 {
+			e.Response.TerminationReason = models.TerminationTimedOut
+		}
+		if err := store.Put(e); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = e.ID
+	}
+
+	expect := func(filter Filter, expected ...int) {
+		t.Helper()
+		res, err := store.List(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != len(expected) {
+			t.Fatalf("listed %d executions (expected: %d)", len(res), len(expected))
+		}
+		for i, e := range res {
+			if e.ID != ids[expected[i]] {
+				t.Errorf("execution %d is %s (expected: %s)", i, e.ID, ids[expected[i]])
+			}
+		}
+	}
+
+	expect(Filter{}, 4, 3, 2, 1, 0)
+	expect(Filter{Language: "go"}, 3, 2, 0)
+	expect(Filter{Language: "go", Offset: 1, Limit: 1}, 2)
+	expect(Filter{TerminationReason: models.TerminationTimedOut}, 3)
+	expect(Filter{Since: start.Add(time.Minute), Until: start.Add(3 * time.Minute)}, 2, 1)
+	expect(Filter{Client: "other"})
+
+	e, ok, err := store.Get(ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || e.Language != "python" || e.Request == nil {
+		t.Errorf("invalid execution: %+v", e)
+	}
+
+	if _, ok, _ = store.Get("unknown"); ok {
+		t.Error("got unknown execution")
+	}
+
+	n, err := store.DeleteBefore(start.Add(2 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("deleted %d executions (expected: 2)", n)
+	}
+	expect(Filter{}, 4, 3, 2)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestBoltStore(t *testing.T) {
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	testStore(t, store)
+}
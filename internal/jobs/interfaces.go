@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+type SandboxManager interface {
+	RunInSandbox(
+		ctx context.Context,
+		req *models.ExecutionRequest,
+		chans sandbox.RunChannels,
+	) (res *sandbox.RunResult, err error)
+	KillAndCleanUp(ctx context.Context, id string) (bool, error)
+}
+
+// Store defines an interface to persist the state
+// and the results of jobs.
+type Store interface {
+
+	// Put stores the given job. If ttl is larger
+	// than 0, the job is removed after ttl has
+	// passed.
+	Put(job *models.Job, ttl time.Duration) error
+
+	// Get returns the job with the given ID. If
+	// no job has been found, ok is false.
+	Get(id string) (job *models.Job, ok bool, err error)
+}
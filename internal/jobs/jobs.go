@@ -0,0 +1,231 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+	"github.com/ranna-go/ranna/pkg/random"
+)
+
+const idLength = 24
+
+var (
+	ErrNotFound          = errors.New("job not found")
+	ErrNotRunning        = errors.New("job is not running")
+	errOutputLenExceeded = errors.New("output len exceeded")
+)
+
+// runningJob holds the state required
+// to cancel a running job.
+type runningJob struct {
+	mtx      sync.Mutex
+	runId    string
+	canceled bool
+	cancel   context.CancelFunc
+}
+
+// Manager runs executions asynchronously as
+// jobs and keeps their results in a Store.
+type Manager struct {
+	sandbox SandboxManager
+	store   Store
+	cfg     ConfigProvider
+	logger  rogu.Logger
+
+	running *sync.Map
+}
+
+// NewManager returns a new instance of Manager.
+func NewManager(sandbox SandboxManager, store Store, cfg ConfigProvider) *Manager {
+	return &Manager{
+		sandbox: sandbox,
+		store:   store,
+		cfg:     cfg,
+		logger:  log.Tagged("Jobs"),
+		running: &sync.Map{},
+	}
+}
+
+// Create creates a new job for the given request
+// and starts its execution in the background. The
+// client identity passed with ctx is carried over
+// to the execution.
+func (t *Manager) Create(ctx context.Context, req *models.ExecutionRequest) (job *models.Job, err error) {
+	id, err := random.GetRandBase64Str(idLength)
+	if err != nil {
+		return nil, err
+	}
+
+	job = &models.Job{
+		ID:      id,
+		Status:  models.JobPending,
+		Created: time.Now(),
+	}
+	if err = t.store.Put(job, 0); err != nil {
+		return nil, err
+	}
+
+	// The job outlives ctx, so only the client identity
+	// and the trace context are passed to the execution.
+	runCtx, cancel := context.WithCancel(trace.ContextWithSpanContext(
+		sandbox.WithClient(context.Background(), sandbox.ClientFromContext(ctx)),
+		trace.SpanContextFromContext(ctx)))
+	rj := &runningJob{cancel: cancel}
+	t.running.Store(id, rj)
+
+	go t.run(runCtx, *job, req, rj)
+
+	return job, nil
+}
+
+// Get returns the job with the given ID.
+func (t *Manager) Get(id string) (job *models.Job, err error) {
+	job, ok, err := t.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return job, nil
+}
+
+// Cancel cancels the job with the given ID. If the
+// sandbox of the job is running, it is killed.
+// Otherwise, the job is removed from the queue.
+func (t *Manager) Cancel(ctx context.Context, id string) (job *models.Job, err error) {
+	if job, err = t.Get(id); err != nil {
+		return nil, err
+	}
+
+	v, ok := t.running.Load(id)
+	if !ok {
+		return nil, ErrNotRunning
+	}
+	rj := v.(*runningJob)
+
+	rj.mtx.Lock()
+	defer rj.mtx.Unlock()
+
+	// If the sandbox is not running (yet), the context
+	// of the execution is canceled instead.
+	rj.canceled = true
+	killed := false
+	if rj.runId != "" {
+		if killed, err = t.sandbox.KillAndCleanUp(ctx, rj.runId); err != nil {
+			return nil, err
+		}
+	}
+	if !killed {
+		rj.cancel()
+	}
+
+	return job, nil
+}
+
+func (t *Manager) run(ctx context.Context, job models.Job, req *models.ExecutionRequest, rj *runningJob) {
+	defer func() {
+		rj.cancel()
+		t.running.Delete(job.ID)
+	}()
+
+	bufferCap, err := util.ParseMemoryStr(t.cfg.Config().Sandbox.StreamBufferCap)
+	if err != nil {
+		t.finish(job, nil, err, rj)
+		return
+	}
+
+	output := sandbox.NewOutputCollector(int(bufferCap))
+	cSpn := make(chan string, 1)
+	cStop := make(chan struct{})
+	cDone := make(chan struct{})
+
+	go func() {
+		defer close(cDone)
+		select {
+		case <-cStop:
+		case runId := <-cSpn:
+			rj.mtx.Lock()
+			rj.runId = runId
+			rj.mtx.Unlock()
+
+			running := job
+			running.Status = models.JobRunning
+			running.RunId = runId
+			if err := t.store.Put(&running, 0); err != nil {
+				t.logger.Error().Err(err).Field("id", job.ID).Msg("failed storing job")
+			}
+		}
+	}()
+
+	var runRes *sandbox.RunResult
+	execTime := util.MeasureTime(func() {
+		runRes, err = t.sandbox.RunInSandbox(ctx, req, sandbox.RunChannels{
+			Spawn:  cSpn,
+			Stdout: output.Stdout,
+			Stderr: output.Stderr,
+		})
+	})
+	stdOut, stdErr := output.Close()
+	close(cStop)
+	<-cDone
+
+	var res *models.ExecutionResponse
+	if err == nil {
+		res = runRes.ToModel(stdOut, stdErr, execTime)
+		err = t.checkOutputLen(res.StdOut, res.StdErr)
+	}
+
+	t.finish(job, res, err, rj)
+}
+
+// finish stores the final state of the job with
+// the given result or error.
+func (t *Manager) finish(job models.Job, res *models.ExecutionResponse, err error, rj *runningJob) {
+	rj.mtx.Lock()
+	canceled := rj.canceled
+	job.RunId = rj.runId
+	rj.mtx.Unlock()
+
+	now := time.Now()
+	job.Finished = &now
+
+	switch {
+	case canceled:
+		job.Status = models.JobCanceled
+		job.Result = res
+	case err != nil:
+		job.Status = models.JobFailed
+		job.Error = sandbox.ErrorModel(err)
+	default:
+		job.Status = models.JobFinished
+		job.Result = res
+	}
+
+	ttl := time.Duration(t.cfg.Config().Jobs.ResultTTLSeconds) * time.Second
+	if err = t.store.Put(&job, ttl); err != nil {
+		t.logger.Error().Err(err).Field("id", job.ID).Msg("failed storing job")
+	}
+}
+
+func (t *Manager) checkOutputLen(stdout, stderr string) (err error) {
+	maxOutLen, err := util.ParseMemoryStr(t.cfg.Config().API.MaxOutputLen)
+	if err != nil {
+		return err
+	}
+
+	if int64(len(stdout))+int64(len(stderr)) > maxOutLen {
+		return errOutputLenExceeded
+	}
+
+	return nil
+}
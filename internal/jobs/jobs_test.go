@@ -0,0 +1,103 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type staticConfig struct {
+	cfg *config.Config
+}
+
+func (t staticConfig) Config() *config.Config {
+	return t.cfg
+}
+
+// fakeSandboxManager runs executions which print
+// their code and block until they are killed if
+// the code is "block".
+type fakeSandboxManager struct {
+	cKill chan struct{}
+}
+
+func (t *fakeSandboxManager) RunInSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	chans sandbox.RunChannels,
+) (*sandbox.RunResult, error) {
+	chans.Spawn <- "run"
+	chans.Stdout <- []byte(req.Code)
+	if req.Code == "block" {
+		<-t.cKill
+		return &sandbox.RunResult{Killed: true}, nil
+	}
+	return &sandbox.RunResult{}, nil
+}
+
+func (t *fakeSandboxManager) KillAndCleanUp(ctx context.Context, id string) (bool, error) {
+	close(t.cKill)
+	return true, nil
+}
+
+func waitForJob(t *testing.T, mgr *Manager, id string) *models.Job {
+	for range 100 {
+		job, err := mgr.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.IsDone() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("job did not finish")
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	sbx := &fakeSandboxManager{cKill: make(chan struct{})}
+	mgr := NewManager(sbx, NewMemoryStore(), staticConfig{&config.Config{
+		API:  config.API{MaxOutputLen: "1M"},
+		Jobs: config.Jobs{ResultTTLSeconds: 60},
+	}})
+
+	if _, err := mgr.Get("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error was %v (expected: %v)", err, ErrNotFound)
+	}
+
+	job, err := mgr.Create(context.Background(), &models.ExecutionRequest{Code: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = waitForJob(t, mgr, job.ID)
+	if job.Status != models.JobFinished || job.Result == nil || job.Result.StdOut != "hello" {
+		t.Errorf("invalid finished job: %+v", job)
+	}
+	if _, err = mgr.Cancel(context.Background(), job.ID); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("error was %v (expected: %v)", err, ErrNotRunning)
+	}
+
+	job, err = mgr.Create(context.Background(), &models.ExecutionRequest{Code: "block"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for job.Status != models.JobRunning {
+		time.Sleep(10 * time.Millisecond)
+		if job, err = mgr.Get(job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = mgr.Cancel(context.Background(), job.ID); err != nil {
+		t.Fatal(err)
+	}
+	job = waitForJob(t, mgr, job.ID)
+	if job.Status != models.JobCanceled || job.Result.TerminationReason != models.TerminationKilled {
+		t.Errorf("invalid canceled job: %+v", job)
+	}
+}
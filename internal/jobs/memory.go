@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+const memoryCleanupInterval = 1 * time.Minute
+
+type memoryEntry struct {
+	job     models.Job
+	expires time.Time
+}
+
+func (t memoryEntry) isExpired(now time.Time) bool {
+	return !t.expires.IsZero() && now.After(t.expires)
+}
+
+// MemoryStore implements Store by keeping
+// jobs in memory.
+type MemoryStore struct {
+	mtx         sync.Mutex
+	jobs        map[string]memoryEntry
+	lastCleanup time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs: make(map[string]memoryEntry),
+	}
+}
+
+func (t *MemoryStore) Put(job *models.Job, ttl time.Duration) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	now := time.Now()
+	t.cleanup(now)
+
+	entry := memoryEntry{job: *job}
+	if ttl > 0 {
+		entry.expires = now.Add(ttl)
+	}
+	t.jobs[job.ID] = entry
+
+	return nil
+}
+
+func (t *MemoryStore) Get(id string) (job *models.Job, ok bool, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	entry, ok := t.jobs[id]
+	if !ok || entry.isExpired(time.Now()) {
+		return nil, false, nil
+	}
+
+	return &entry.job, true, nil
+}
+
+// cleanup removes all expired jobs, at most
+// once per cleanup interval.
+func (t *MemoryStore) cleanup(now time.Time) {
+	if now.Sub(t.lastCleanup) < memoryCleanupInterval {
+		return
+	}
+	t.lastCleanup = now
+
+	for id, entry := range t.jobs {
+		if entry.isExpired(now) {
+			delete(t.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package metrics
+
+import "github.com/ranna-go/ranna/internal/config"
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+type SandboxManager interface {
+	RunningCount() int
+	QueueLength() int
+}
@@ -0,0 +1,122 @@
+// Package metrics defines the Prometheus metrics
+// of ranna and serves them on a separate address.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "ranna"
+
+// Outcomes of executions which did not
+// finish with a termination reason.
+const (
+	OutcomeInvalid     = "invalid"
+	OutcomeUnavailable = "unavailable"
+	OutcomeCanceled    = "canceled"
+	OutcomeError       = "error"
+)
+
+// Results of image pulls and spec reloads.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	// Executions counts the executions by language and
+	// outcome. The outcome is the termination reason of
+	// finished executions or one of the Outcome* values.
+	Executions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "executions_total",
+		Help:      "Number of executions by language and outcome.",
+	}, []string{"language", "outcome"})
+
+	// SandboxCreateDuration observes the time it takes
+	// to create a sandbox.
+	SandboxCreateDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sandbox_create_duration_seconds",
+		Help:      "Time it takes to create a sandbox.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"language"})
+
+	// SandboxRunDuration observes the time a
+	// sandbox is running.
+	SandboxRunDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sandbox_run_duration_seconds",
+		Help:      "Time a sandbox is running.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"language"})
+
+	// SandboxCleanupDuration observes the time it takes
+	// to kill and delete a sandbox.
+	SandboxCleanupDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sandbox_cleanup_duration_seconds",
+		Help:      "Time it takes to kill and delete a sandbox.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"language"})
+
+	// WSConnections is the number of open
+	// WebSocket connections.
+	WSConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ws_connections",
+		Help:      "Number of open WebSocket connections.",
+	})
+
+	// RateLimitRejections counts the requests rejected
+	// by the name of the rate limit or "quota".
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ratelimit_rejections_total",
+		Help:      "Number of requests rejected by rate limits and quotas.",
+	}, []string{"limit"})
+
+	// ImagePulls counts the image pulls by
+	// image and result.
+	ImagePulls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_pulls_total",
+		Help:      "Number of image pulls by image and result.",
+	}, []string{"image", "result"})
+
+	// SpecReloads counts the reloads of
+	// the spec map by result.
+	SpecReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "spec_reloads_total",
+		Help:      "Number of spec map reloads by result.",
+	}, []string{"result"})
+)
+
+// RegisterSandboxManager registers the gauges of
+// the running sandboxes and the queue length of
+// the given manager.
+func RegisterSandboxManager(mgr SandboxManager) {
+	prometheus.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "running_sandboxes",
+			Help:      "Number of executions with a running sandbox.",
+		}, func() float64 { return float64(mgr.RunningCount()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_length",
+			Help:      "Number of executions waiting for a free slot.",
+		}, func() float64 { return float64(mgr.QueueLength()) }),
+	)
+}
+
+// Result returns ResultFailure if err is
+// not nil. Otherwise, ResultSuccess.
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
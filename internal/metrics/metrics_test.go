@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ranna-go/ranna/internal/config"
+)
+
+type staticConfig struct {
+	c *config.Config
+}
+
+func (t staticConfig) Config() *config.Config { return t.c }
+
+type fakeSandboxManager struct{}
+
+func (fakeSandboxManager) RunningCount() int { return 3 }
+func (fakeSandboxManager) QueueLength() int  { return 7 }
+
+func TestServer(t *testing.T) {
+	RegisterSandboxManager(fakeSandboxManager{})
+	Executions.WithLabelValues("python3", "exited").Inc()
+	RateLimitRejections.WithLabelValues("exec").Inc()
+
+	srv := NewServer(staticConfig{&config.Config{}})
+	rec := httptest.NewRecorder()
+	srv.server.Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+	for _, expected := range []string{
+		`ranna_executions_total{language="python3",outcome="exited"} 1`,
+		`ranna_ratelimit_rejections_total{limit="exec"} 1`,
+		`ranna_running_sandboxes 3`,
+		`ranna_queue_length 7`,
+		`ranna_ws_connections 0`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("metrics do not contain %q", expected)
+		}
+	}
+}
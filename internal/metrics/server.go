@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/zekrotja/rogu/log"
+)
+
+// Server serves the metrics at /metrics.
+type Server struct {
+	bindAddress string
+	server      *http.Server
+}
+
+// NewServer returns a new instance of Server
+// listening on the configured bind address.
+func NewServer(cfg ConfigProvider) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	bindAddress := cfg.Config().Metrics.BindAddress
+	return &Server{
+		bindAddress: bindAddress,
+		server: &http.Server{
+			Addr:    bindAddress,
+			Handler: mux,
+		},
+	}
+}
+
+func (t *Server) ListenAndServeBlocking() error {
+	log.Info().Field("addr", t.bindAddress).Msg("Starting metrics server ...")
+	err := t.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops the server gracefully.
+func (t *Server) Shutdown(ctx context.Context) error {
+	return t.server.Shutdown(ctx)
+}
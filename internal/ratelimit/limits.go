@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the limits shared between the APIs.
+const (
+	// LimitExec limits all executions, regardless
+	// of the API they are requested by.
+	LimitExec = "exec"
+	// LimitDefault applies to all endpoints and
+	// operations without a limit of their own.
+	LimitDefault = "default"
+)
+
+// Limit allows Burst requests which are
+// regenerated evenly over Period.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// interval returns the duration after
+// which a single request is regenerated.
+func (t Limit) interval() time.Duration {
+	return t.Period / time.Duration(t.Burst)
+}
+
+// parseLimits parses a space separated list of limits
+// in the format <name>:<burst>/<period>, where period
+// is a duration like 10s or 1m.
+func parseLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, def := range strings.Fields(s) {
+		name, spec, ok := strings.Cut(def, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid limit: %s", def)
+		}
+		burstStr, periodStr, ok := strings.Cut(spec, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid limit: %s", def)
+		}
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil || burst <= 0 {
+			return nil, fmt.Errorf("invalid limit burst: %s", def)
+		}
+		period, err := time.ParseDuration(periodStr)
+		if err != nil || period <= 0 {
+			return nil, fmt.Errorf("invalid limit period: %s", def)
+		}
+		limits[name] = Limit{Burst: burst, Period: period}
+	}
+	return limits, nil
+}
@@ -0,0 +1,263 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zekroTJA/ratelimit"
+	"github.com/zekroTJA/timedmap/v2"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/metrics"
+)
+
+const (
+	cleanupInterval = 15 * time.Minute
+	entryLifetime   = 1 * time.Hour
+)
+
+var (
+	ErrRateLimited   = errors.New("you have been rate limited")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// Result describes the outcome of a rate limit
+// or quota check.
+type Result struct {
+	// Allowed is false if the request has been
+	// rejected. Err contains the reason then.
+	Allowed bool
+	Err     error
+
+	// Limit, Remaining and Reset describe the state of
+	// the applied rate limit. Limit is 0 if no rate
+	// limit applies.
+	Limit     int
+	Remaining int
+	Reset     time.Time
+
+	// RetryAfter is the duration after which a
+	// rejected request can be retried.
+	RetryAfter time.Duration
+}
+
+// usage holds the executions and CPU time
+// of a client in the current day and month.
+type usage struct {
+	day        string
+	month      string
+	dayExecs   int
+	monthExecs int
+	dayCPU     time.Duration
+	monthCPU   time.Duration
+}
+
+// Manager enforces rate limits per endpoint or
+// operation and execution quotas per client. It
+// is shared between all APIs so that the limits
+// can not be bypassed by switching the API.
+//
+// Clients are identified by the name of their API
+// key or, if unauthenticated, by their address.
+type Manager struct {
+	limits map[string]Limit
+	quota  auth.Quota
+	now    func() time.Time
+
+	mtx      sync.Mutex
+	limiters *timedmap.TimedMap[string, *ratelimit.Limiter]
+	usage    *timedmap.TimedMap[string, *usage]
+}
+
+// NewManager returns a new instance of Manager
+// with the configured limits and quotas.
+func NewManager(cfg ConfigProvider) (*Manager, error) {
+	c := cfg.Config().RateLimit
+
+	limits, err := parseLimits(c.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	// The deprecated WebSocket rate limit config
+	// applies to all executions if no exec limit
+	// is configured.
+	ws := cfg.Config().API.WS.RateLimit
+	if _, ok := limits[LimitExec]; !ok && ws.Burst > 0 && ws.LimitSeconds > 0 {
+		limits[LimitExec] = Limit{
+			Burst:  ws.Burst,
+			Period: time.Duration(ws.LimitSeconds*ws.Burst) * time.Second,
+		}
+	}
+
+	return &Manager{
+		limits: limits,
+		quota: auth.Quota{
+			DailyExecutions:   c.DailyExecutions,
+			MonthlyExecutions: c.MonthlyExecutions,
+			DailyCPUSeconds:   c.DailyCPUSeconds,
+			MonthlyCPUSeconds: c.MonthlyCPUSeconds,
+		},
+		now:      time.Now,
+		limiters: timedmap.New[string, *ratelimit.Limiter](cleanupInterval),
+		usage:    timedmap.New[string, *usage](cleanupInterval),
+	}, nil
+}
+
+// Allow takes a request of the given identity from the
+// rate limit with the given name. If no limit with the
+// name is configured, the default limit applies.
+func (t *Manager) Allow(id *auth.Identity, name string) Result {
+	limit, ok := t.limits[name]
+	if !ok {
+		if limit, ok = t.limits[LimitDefault]; !ok {
+			return Result{Allowed: true}
+		}
+	}
+
+	now := t.now()
+	ok, res := t.limiter(name+"::"+id.Client(), limit).Reserve()
+
+	result := Result{
+		Allowed:   ok,
+		Limit:     res.Burst,
+		Remaining: res.Remaining,
+		Reset:     now,
+	}
+	if !res.Reset.IsNil() {
+		result.Reset = res.Reset.Time
+	}
+	if !ok {
+		metrics.RateLimitRejections.WithLabelValues(name).Inc()
+		result.Err = ErrRateLimited
+		result.RetryAfter = max(result.Reset.Sub(now), 0)
+	}
+
+	return result
+}
+
+// ReserveExecution counts an execution of the given
+// identity if it is within its quotas. Otherwise, the
+// execution is rejected.
+func (t *Manager) ReserveExecution(id *auth.Identity) Result {
+	q := t.quotaFor(id)
+	if q.DailyExecutions <= 0 && q.MonthlyExecutions <= 0 &&
+		q.DailyCPUSeconds <= 0 && q.MonthlyCPUSeconds <= 0 {
+		return Result{Allowed: true}
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	now := t.now().UTC()
+	u := t.getUsage(id.Client(), now)
+
+	nextDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+	reject := func(reason string, reset time.Time) Result {
+		metrics.RateLimitRejections.WithLabelValues("quota").Inc()
+		return Result{
+			Err:        fmt.Errorf("%w: %s", ErrQuotaExceeded, reason),
+			RetryAfter: reset.Sub(now),
+		}
+	}
+
+	switch {
+	case q.MonthlyExecutions > 0 && u.monthExecs >= q.MonthlyExecutions:
+		return reject("monthly executions", nextMonth)
+	case q.MonthlyCPUSeconds > 0 && u.monthCPU >= time.Duration(q.MonthlyCPUSeconds)*time.Second:
+		return reject("monthly CPU time", nextMonth)
+	case q.DailyExecutions > 0 && u.dayExecs >= q.DailyExecutions:
+		return reject("daily executions", nextDay)
+	case q.DailyCPUSeconds > 0 && u.dayCPU >= time.Duration(q.DailyCPUSeconds)*time.Second:
+		return reject("daily CPU time", nextDay)
+	}
+
+	u.dayExecs++
+	u.monthExecs++
+
+	return Result{Allowed: true}
+}
+
+// RefundExecution takes back an execution of the given
+// client counted by ReserveExecution which has not been
+// run, i.e. because the request has been rejected.
+func (t *Manager) RefundExecution(client string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	u := t.getUsage(client, t.now().UTC())
+	u.dayExecs = max(u.dayExecs-1, 0)
+	u.monthExecs = max(u.monthExecs-1, 0)
+}
+
+// AddCPUTime accounts the given CPU time to
+// the quotas of the given client.
+func (t *Manager) AddCPUTime(client string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	u := t.getUsage(client, t.now().UTC())
+	u.dayCPU += d
+	u.monthCPU += d
+}
+
+// quotaFor returns the quotas of the identity
+// merged with the configured quotas.
+func (t *Manager) quotaFor(id *auth.Identity) auth.Quota {
+	merge := func(v, def int) int {
+		if v != 0 {
+			return v
+		}
+		return def
+	}
+	return auth.Quota{
+		DailyExecutions:   merge(id.Quota.DailyExecutions, t.quota.DailyExecutions),
+		MonthlyExecutions: merge(id.Quota.MonthlyExecutions, t.quota.MonthlyExecutions),
+		DailyCPUSeconds:   merge(id.Quota.DailyCPUSeconds, t.quota.DailyCPUSeconds),
+		MonthlyCPUSeconds: merge(id.Quota.MonthlyCPUSeconds, t.quota.MonthlyCPUSeconds),
+	}
+}
+
+// limiter returns the limiter stored under key or
+// creates a new one with the given limit.
+func (t *Manager) limiter(key string, limit Limit) *ratelimit.Limiter {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if limiter, ok := t.limiters.GetValue(key); ok {
+		return limiter
+	}
+	limiter := ratelimit.NewLimiterWithTimeSource(t.now, limit.interval(), limit.Burst)
+	t.limiters.Set(key, limiter, entryLifetime)
+	return limiter
+}
+
+// getUsage returns the usage of the client in the
+// current day and month. Usages expire at the end
+// of the month. t.mtx must be held.
+func (t *Manager) getUsage(client string, now time.Time) *usage {
+	day := now.Format(time.DateOnly)
+	month := now.Format("2006-01")
+
+	u, ok := t.usage.GetValue(client)
+	if !ok || u.month != month {
+		u = &usage{day: day, month: month}
+		nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		t.usage.Set(client, u, nextMonth.Sub(now))
+	}
+	if u.day != day {
+		u.day = day
+		u.dayExecs = 0
+		u.dayCPU = 0
+	}
+
+	return u
+}
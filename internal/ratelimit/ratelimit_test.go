@@ -0,0 +1,258 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/auth"
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type staticConfig struct {
+	c *config.Config
+}
+
+func (t staticConfig) Config() *config.Config { return t.c }
+
+// clock is a manually advanced time source.
+type clock struct {
+	now time.Time
+}
+
+func (t *clock) Now() time.Time { return t.now }
+
+func newTestManager(t *testing.T, c *config.Config) (*Manager, *clock) {
+	m, err := NewManager(staticConfig{c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk := &clock{now: time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC)}
+	m.now = clk.Now
+	return m, clk
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := parseLimits("exec:5/10s  spec:100/1m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := limits["exec"]; l.Burst != 5 || l.Period != 10*time.Second || l.interval() != 2*time.Second {
+		t.Errorf("invalid exec limit: %+v", l)
+	}
+	if l := limits["spec"]; l.Burst != 100 || l.Period != time.Minute {
+		t.Errorf("invalid spec limit: %+v", l)
+	}
+
+	for _, s := range []string{"exec", ":5/1s", "exec:5", "exec:0/1s", "exec:a/1s", "exec:5/1", "exec:5/-1s"} {
+		if _, err = parseLimits(s); err == nil {
+			t.Errorf("no error returned for %q", s)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	m, clk := newTestManager(t, &config.Config{RateLimit: config.RateLimiting{
+		Limits: "exec:2/10s default:1/1s",
+	}})
+
+	a := &auth.Identity{Addr: "1.2.3.4"}
+	b := &auth.Identity{Name: "b", Addr: "1.2.3.4"}
+
+	for i, expected := range []bool{true, true, false} {
+		res := m.Allow(a, LimitExec)
+		if res.Allowed != expected {
+			t.Fatalf("request %d: allowed = %t (expected: %t)", i, res.Allowed, expected)
+		}
+		if res.Limit != 2 || res.Remaining != 1-min(i, 1) {
+			t.Errorf("request %d: invalid state: %+v", i, res)
+		}
+	}
+
+	res := m.Allow(a, LimitExec)
+	if !errors.Is(res.Err, ErrRateLimited) || res.RetryAfter <= 0 || res.RetryAfter > 5*time.Second {
+		t.Errorf("invalid rejection: %+v", res)
+	}
+
+	if !m.Allow(b, LimitExec).Allowed {
+		t.Error("limit is shared between clients")
+	}
+
+	clk.now = clk.now.Add(5 * time.Second)
+	if !m.Allow(a, LimitExec).Allowed {
+		t.Error("limit has not been regenerated")
+	}
+
+	// Endpoints without a limit of their own have
+	// a separate bucket of the default limit.
+	if !m.Allow(a, "spec").Allowed || !m.Allow(a, "info").Allowed {
+		t.Error("default limit is shared between endpoints")
+	}
+	if m.Allow(a, "spec").Allowed {
+		t.Error("default limit has not been applied")
+	}
+}
+
+func TestAllowUnlimited(t *testing.T) {
+	m, _ := newTestManager(t, &config.Config{})
+	for range 100 {
+		if res := m.Allow(&auth.Identity{Addr: "1.2.3.4"}, LimitExec); !res.Allowed || res.Limit != 0 {
+			t.Fatalf("invalid result: %+v", res)
+		}
+	}
+}
+
+func TestDeprecatedWSLimit(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.API.WS.RateLimit.Burst = 3
+	cfg.API.WS.RateLimit.LimitSeconds = 2
+
+	m, _ := newTestManager(t, cfg)
+	if l := m.limits[LimitExec]; l.Burst != 3 || l.interval() != 2*time.Second {
+		t.Errorf("invalid exec limit: %+v", l)
+	}
+
+	cfg.RateLimit.Limits = "exec:1/1s"
+	m, _ = newTestManager(t, cfg)
+	if l := m.limits[LimitExec]; l.Burst != 1 {
+		t.Errorf("configured exec limit has been overridden: %+v", l)
+	}
+}
+
+func TestReserveExecution(t *testing.T) {
+	m, clk := newTestManager(t, &config.Config{RateLimit: config.RateLimiting{
+		DailyExecutions:   2,
+		MonthlyExecutions: 3,
+	}})
+
+	id := &auth.Identity{Addr: "1.2.3.4"}
+	expect := func(expected bool) Result {
+		t.Helper()
+		res := m.ReserveExecution(id)
+		if res.Allowed != expected {
+			t.Fatalf("allowed = %t (expected: %t): %v", res.Allowed, expected, res.Err)
+		}
+		return res
+	}
+
+	expect(true)
+	expect(true)
+	res := expect(false)
+	if !errors.Is(res.Err, ErrQuotaExceeded) || res.RetryAfter != time.Hour {
+		t.Errorf("invalid daily rejection: %+v", res)
+	}
+
+	// The next day is in the next month, so
+	// both quotas are reset.
+	clk.now = clk.now.Add(2 * time.Hour)
+	expect(true)
+	expect(true)
+
+	clk.now = clk.now.Add(24 * time.Hour)
+	expect(true)
+	res = expect(false)
+	if nextMonth := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC); res.RetryAfter != nextMonth.Sub(clk.now) {
+		t.Errorf("invalid monthly rejection: %+v", res)
+	}
+
+	// Negative quotas of the identity disable
+	// the configured quotas.
+	id = &auth.Identity{Name: "unlimited", Quota: auth.Quota{DailyExecutions: -1, MonthlyExecutions: -1}}
+	for range 10 {
+		expect(true)
+	}
+}
+
+func TestCPUQuota(t *testing.T) {
+	m, _ := newTestManager(t, &config.Config{RateLimit: config.RateLimiting{
+		DailyCPUSeconds: 10,
+	}})
+
+	id := &auth.Identity{Name: "a", Quota: auth.Quota{DailyCPUSeconds: 5}}
+
+	if !m.ReserveExecution(id).Allowed {
+		t.Fatal("execution has been rejected")
+	}
+	m.AddCPUTime(id.Client(), 5*time.Second)
+	if m.ReserveExecution(id).Allowed {
+		t.Error("CPU quota of identity has not been applied")
+	}
+
+	other := &auth.Identity{Name: "b"}
+	m.AddCPUTime(other.Client(), 5*time.Second)
+	if !m.ReserveExecution(other).Allowed {
+		t.Error("execution within configured CPU quota has been rejected")
+	}
+}
+
+type fakeSandboxManager struct {
+	SandboxManager
+
+	res *sandbox.RunResult
+	err error
+}
+
+func (t fakeSandboxManager) RunInSandbox(
+	context.Context, *models.ExecutionRequest, sandbox.RunChannels,
+) (*sandbox.RunResult, error) {
+	return t.res, t.err
+}
+
+func TestUsageRecorder(t *testing.T) {
+	m, _ := newTestManager(t, &config.Config{RateLimit: config.RateLimiting{
+		MonthlyCPUSeconds: 3,
+	}})
+
+	run := func(res *sandbox.RunResult) {
+		t.Helper()
+		r := NewUsageRecorder(fakeSandboxManager{res: res}, m)
+		ctx := sandbox.WithClient(context.Background(), "1.2.3.4")
+		if _, err := r.RunInSandbox(ctx, &models.ExecutionRequest{}, sandbox.RunChannels{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	id := &auth.Identity{Addr: "1.2.3.4"}
+
+	run(&sandbox.RunResult{Usage: &models.ResourceUsage{CPUTimeMS: 5000}, Cached: true})
+	if !m.ReserveExecution(id).Allowed {
+		t.Fatal("CPU time of cached result has been accounted")
+	}
+
+	run(&sandbox.RunResult{Usage: &models.ResourceUsage{CPUTimeMS: 3000}})
+	if m.ReserveExecution(id).Allowed {
+		t.Error("CPU time has not been accounted")
+	}
+}
+
+func TestUsageRecorderRefund(t *testing.T) {
+	m, _ := newTestManager(t, &config.Config{RateLimit: config.RateLimiting{
+		DailyExecutions: 1,
+	}})
+
+	id := &auth.Identity{Addr: "1.2.3.4"}
+	ctx := sandbox.WithClient(context.Background(), id.Client())
+
+	run := func(mgr SandboxManager) {
+		t.Helper()
+		if !m.ReserveExecution(id).Allowed {
+			t.Fatal("execution has been rejected")
+		}
+		NewUsageRecorder(mgr, m).RunInSandbox(ctx, &models.ExecutionRequest{}, sandbox.RunChannels{})
+	}
+
+	run(fakeSandboxManager{err: sandbox.NewRejectedError(sandbox.ErrQueueFull)})
+	if !m.ReserveExecution(id).Allowed {
+		t.Fatal("rejected execution has not been refunded")
+	}
+	m.RefundExecution(id.Client())
+
+	// Executions canceled after the spawn are counted.
+	run(fakeSandboxManager{err: context.Canceled})
+	if m.ReserveExecution(id).Allowed {
+		t.Error("canceled execution has been refunded")
+	}
+}
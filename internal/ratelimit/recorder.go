@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// UsageRecorder wraps a sandbox manager and accounts
+// the CPU time used by executions to the quotas of
+// the clients which requested them. Executions which
+// are rejected before a sandbox has been spawned are
+// refunded to the quotas.
+type UsageRecorder struct {
+	SandboxManager
+
+	limiter *Manager
+}
+
+// NewUsageRecorder returns a new instance of
+// UsageRecorder accounting to limiter.
+func NewUsageRecorder(manager SandboxManager, limiter *Manager) *UsageRecorder {
+	return &UsageRecorder{
+		SandboxManager: manager,
+		limiter:        limiter,
+	}
+}
+
+// RunInSandbox runs the given request using the wrapped
+// manager and accounts the CPU time of the build and run
+// phase. Cached results do not use any CPU time.
+//
+// If the execution is rejected before a sandbox has been
+// spawned, for example because the request is invalid or
+// the queue is full, the execution reserved for the request
+// is refunded. Executions which fail or are canceled after
+// the spawn are still counted.
+func (t *UsageRecorder) RunInSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	chans sandbox.RunChannels,
+) (res *sandbox.RunResult, err error) {
+	res, err = t.SandboxManager.RunInSandbox(ctx, req, chans)
+	if err != nil {
+		if sandbox.IsRejectedError(err) {
+			t.limiter.RefundExecution(sandbox.ClientFromContext(ctx))
+		}
+		return nil, err
+	}
+	if res.Cached {
+		return res, nil
+	}
+
+	var cpuMS int64
+	if res.Usage != nil {
+		cpuMS += res.Usage.CPUTimeMS
+	}
+	if res.Build != nil && res.Build.Usage != nil && res.Build.Usage != res.Usage {
+		cpuMS += res.Build.Usage.CPUTimeMS
+	}
+	t.limiter.AddCPUTime(sandbox.ClientFromContext(ctx), time.Duration(cpuMS)*time.Millisecond)
+
+	return res, nil
+}
@@ -0,0 +1,119 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+var errInvalidArtifactPattern = errors.New("invalid artifact pattern")
+
+// validateArtifacts checks that all artifact patterns
+// passed in req are valid glob patterns which are
+// local to the working directory.
+func validateArtifacts(req *models.ExecutionRequest) error {
+	for _, pattern := range req.Artifacts {
+		if !filepath.IsLocal(pattern) {
+			return fmt.Errorf("%w: %s", errInvalidArtifactPattern, pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: %s", errInvalidArtifactPattern, pattern)
+		}
+	}
+	return nil
+}
+
+// collectArtifacts reads the files matching the given
+// patterns from the host directory hostDir within the
+// artifact limits set in the config.
+func (t *Manager) collectArtifacts(hostDir string, patterns []string) (
+	artifacts []models.Artifact,
+	truncated bool,
+	err error,
+) {
+	cfg := t.cfg.Config().Sandbox.Artifacts
+
+	maxSize, err := util.ParseMemoryStr(cfg.MaxSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = t.file.OpenDirectory(hostDir, func(fsys fs.FS) (err error) {
+		artifacts, truncated, err = readArtifacts(fsys, patterns, cfg.MaxCount, maxSize)
+		return err
+	})
+
+	return artifacts, truncated, err
+}
+
+// readArtifacts reads all regular files in fsys which
+// match any of the given patterns in lexical order.
+//
+// Files which would exceed maxCount files or maxSize
+// bytes in total are skipped and truncated is set to
+// true. A limit of 0 means no limit.
+func readArtifacts(fsys fs.FS, patterns []string, maxCount int, maxSize int64) (
+	artifacts []models.Artifact,
+	truncated bool,
+	err error,
+) {
+	var names []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, path.Clean(pattern))
+		if err != nil {
+			return nil, false, err
+		}
+		names = append(names, matches...)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	var size int64
+	for _, name := range names {
+		// Files which can not be accessed, i.e. symlinks
+		// pointing outside of the directory, are skipped.
+		info, err := fs.Stat(fsys, name)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		if (maxCount > 0 && len(artifacts) >= maxCount) ||
+			(maxSize > 0 && size+info.Size() > maxSize) {
+			truncated = true
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, false, err
+		}
+		size += int64(len(data))
+
+		artifacts = append(artifacts, models.Artifact{
+			Name:     name,
+			MimeType: detectMimeType(name, data),
+			Size:     int64(len(data)),
+			Data:     data,
+		})
+	}
+
+	return artifacts, truncated, nil
+}
+
+// detectMimeType returns the MIME type of a file by
+// its extension or, if the extension is unknown, by
+// its content.
+func detectMimeType(name string, data []byte) string {
+	if typ := mime.TypeByExtension(path.Ext(name)); typ != "" {
+		return typ
+	}
+	return http.DetectContentType(data)
+}
@@ -0,0 +1,71 @@
+package sandbox
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+func TestValidateArtifacts(t *testing.T) {
+	expect := func(patterns []string, expErr error) {
+		err := validateArtifacts(&models.ExecutionRequest{Artifacts: patterns})
+		if !errors.Is(err, expErr) {
+			t.Errorf("error for %v was %v (expected: %v)", patterns, err, expErr)
+		}
+	}
+
+	expect(nil, nil)
+	expect([]string{"*.png", "out/data.csv"}, nil)
+	expect([]string{"/etc/passwd"}, errInvalidArtifactPattern)
+	expect([]string{"../*"}, errInvalidArtifactPattern)
+	expect([]string{"[a-"}, errInvalidArtifactPattern)
+}
+
+func TestReadArtifacts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.py":      {Data: []byte("print()")},
+		"plot.png":     {Data: []byte("\x89PNG\r\n\x1a\n")},
+		"out/data.csv": {Data: []byte("a,b\n1,2\n")},
+		"out/blob":     {Data: []byte("hello")},
+		"out/dir/x":    {Data: []byte("x")},
+	}
+
+	artifacts, truncated, err := readArtifacts(fsys, []string{"*.png", "out/*", "./plot.png"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncated {
+		t.Error("artifacts have been truncated")
+	}
+	names := make([]string, len(artifacts))
+	for i, a := range artifacts {
+		names[i] = a.Name
+	}
+	if len(names) != 3 || names[0] != "out/blob" || names[1] != "out/data.csv" || names[2] != "plot.png" {
+		t.Fatalf("invalid artifacts: %v", names)
+	}
+	if artifacts[0].MimeType != "text/plain; charset=utf-8" {
+		t.Errorf("invalid MIME type of blob: %s", artifacts[0].MimeType)
+	}
+	if artifacts[2].MimeType != "image/png" || artifacts[2].Size != 8 {
+		t.Errorf("invalid png artifact: %+v", artifacts[2])
+	}
+
+	artifacts, truncated, err = readArtifacts(fsys, []string{"out/*"}, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !truncated || len(artifacts) != 1 {
+		t.Errorf("invalid artifacts with count limit: %d (truncated: %t)", len(artifacts), truncated)
+	}
+
+	artifacts, truncated, err = readArtifacts(fsys, []string{"out/*"}, 0, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !truncated || len(artifacts) != 1 || artifacts[0].Name != "out/blob" {
+		t.Errorf("invalid artifacts with size limit: %v (truncated: %t)", artifacts, truncated)
+	}
+}
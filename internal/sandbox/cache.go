@@ -0,0 +1,250 @@
+package sandbox
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// cacheEntry holds a cached execution result
+// together with its output.
+type cacheEntry struct {
+	key     string
+	image   string
+	res     RunResult
+	stdout  string
+	stderr  string
+	size    int64
+	expires time.Time
+}
+
+// resultCache keeps the results of deterministic
+// executions for a given time. When the total size
+// of the cached results exceeds maxSize, the least
+// recently used results are evicted.
+type resultCache struct {
+	ttl     time.Duration
+	maxSize int64
+
+	mtx     sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+}
+
+func newResultCache(ttl time.Duration, maxSize int64) *resultCache {
+	return &resultCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// enabled returns true if results are cached.
+func (t *resultCache) enabled() bool {
+	return t.ttl > 0
+}
+
+// get returns the cached entry for the given key
+// if it exists and has not expired.
+func (t *resultCache) get(key string) (*cacheEntry, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	el, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if time.Now().After(e.expires) {
+		t.remove(el)
+		return nil, false
+	}
+	t.lru.MoveToFront(el)
+	return e, true
+}
+
+// put stores the given result with its output.
+// Results which are larger than maxSize on their
+// own are not cached.
+func (t *resultCache) put(key, image string, res RunResult, stdout, stderr string) {
+	e := &cacheEntry{
+		key:     key,
+		image:   image,
+		res:     res,
+		stdout:  stdout,
+		stderr:  stderr,
+		expires: time.Now().Add(t.ttl),
+	}
+	e.size = int64(len(stdout) + len(stderr))
+	if res.Build != nil {
+		e.size += int64(len(res.Build.StdOut) + len(res.Build.StdErr))
+	}
+	for _, a := range res.Artifacts {
+		e.size += int64(len(a.Data))
+	}
+
+	if t.maxSize > 0 && e.size > t.maxSize {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if el, ok := t.entries[key]; ok {
+		t.remove(el)
+	}
+	t.entries[key] = t.lru.PushFront(e)
+	t.size += e.size
+
+	for t.maxSize > 0 && t.size > t.maxSize {
+		t.remove(t.lru.Back())
+	}
+}
+
+// invalidate removes all cached results of
+// executions using the given image.
+func (t *resultCache) invalidate(image string) (n int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for _, el := range t.entries {
+		if el.Value.(*cacheEntry).image == image {
+			t.remove(el)
+			n++
+		}
+	}
+	return n
+}
+
+func (t *resultCache) remove(el *list.Element) {
+	e := t.lru.Remove(el).(*cacheEntry)
+	delete(t.entries, e.key)
+	t.size -= e.size
+}
+
+// cacheable returns true if the result of the
+// execution is deterministic, i.e. it has not
+// been interrupted by a limit or a kill.
+func (t RunResult) cacheable() bool {
+	return !t.TimedOut && !t.OOMKilled && !t.Killed
+}
+
+// cacheKey returns the key of the result of the given
+// request which is the hash of all inputs affecting the
+// result, including the digest of the used image.
+func (t *Manager) cacheKey(
+	ctx context.Context,
+	spc models.Spec,
+	req *models.ExecutionRequest,
+	lim ResourceLimits,
+) (string, error) {
+	digest, err := t.imageDigest(ctx, spc.Image)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(struct {
+		Spec         models.Spec
+		ImageDigest  string
+		Code         string
+		Files        map[string]string
+		Arguments    []string
+		Environment  map[string]string
+		Stdin        string
+		Artifacts    []string
+		Limits       ResourceLimits
+		Timeout      time.Duration
+		BuildTimeout time.Duration
+	}{
+		Spec:         spc,
+		ImageDigest:  digest,
+		Code:         req.Code,
+		Files:        req.Files,
+		Arguments:    req.Arguments,
+		Environment:  req.Environment,
+		Stdin:        req.Stdin,
+		Artifacts:    req.Artifacts,
+		Limits:       lim,
+		Timeout:      lim.Timeout,
+		BuildTimeout: lim.BuildTimeout,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// imageDigest returns the digest of the given image
+// as known from the last preparation of the image.
+func (t *Manager) imageDigest(ctx context.Context, image string) (string, error) {
+	if image == "" {
+		return "", nil
+	}
+	if digest, ok := t.digests.Load(image); ok {
+		return digest.(string), nil
+	}
+	digest, err := t.sandbox.ImageDigest(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	t.digests.Store(image, digest)
+	return digest, nil
+}
+
+// updateImageDigest fetches the current digest of the
+// given image and invalidates all cached results of
+// the image if the digest has changed.
+func (t *Manager) updateImageDigest(ctx context.Context, image string) error {
+	digest, err := t.sandbox.ImageDigest(ctx, image)
+	if err != nil {
+		return err
+	}
+	prev, ok := t.digests.Swap(image, digest)
+	if ok && prev.(string) != digest {
+		n := t.cache.invalidate(image)
+		t.logger.Info().Fields("image", image, "n", n).Msg("invalidated cached results")
+	}
+	return nil
+}
+
+// replayCached passes the output and phase events of
+// the cached entry into chans and returns a copy of
+// the cached result flagged as cached.
+func (t *Manager) replayCached(e *cacheEntry, chans RunChannels) *RunResult {
+	runId := "cached-" + e.key[:12]
+	if chans.Spawn != nil {
+		chans.Spawn <- runId
+	}
+
+	if e.res.Build != nil {
+		chans.sendPhase(PhaseEvent{Phase: PhaseBuildStart, RunId: runId})
+		chans.sendPhase(PhaseEvent{Phase: PhaseBuildEnd, RunId: runId, Build: e.res.Build})
+		if e.res.BuildFailed {
+			res := e.res
+			res.Cached = true
+			return &res
+		}
+		chans.sendPhase(PhaseEvent{Phase: PhaseRunStart, RunId: runId})
+	}
+
+	if e.stdout != "" {
+		chans.Stdout <- []byte(e.stdout)
+	}
+	if e.stderr != "" {
+		chans.Stderr <- []byte(e.stderr)
+	}
+
+	res := e.res
+	res.Cached = true
+	return &res
+}
@@ -0,0 +1,97 @@
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+func TestResultCache(t *testing.T) {
+	c := newResultCache(time.Minute, 10)
+
+	c.put("a", "go", RunResult{ExitCode: 1}, "1234", "")
+	c.put("b", "python", RunResult{ExitCode: 2}, "1234", "")
+	if e, ok := c.get("a"); !ok || e.res.ExitCode != 1 || e.stdout != "1234" {
+		t.Errorf("invalid entry: %+v", e)
+	}
+
+	// "b" is the least recently used entry and is
+	// evicted when the max size is exceeded.
+	c.put("c", "go", RunResult{}, "1234", "")
+	if _, ok := c.get("b"); ok {
+		t.Error("least recently used entry has not been evicted")
+	}
+
+	c.put("d", "go", RunResult{}, "01234567890", "")
+	if _, ok := c.get("d"); ok {
+		t.Error("entry exceeding the max size has been cached")
+	}
+
+	if n := c.invalidate("go"); n != 2 {
+		t.Errorf("invalidated %d entries (expected: 2)", n)
+	}
+	if c.size != 0 || len(c.entries) != 0 {
+		t.Errorf("cache is not empty: size=%d entries=%d", c.size, len(c.entries))
+	}
+
+	c = newResultCache(time.Millisecond, 0)
+	c.put("a", "go", RunResult{}, "", "")
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.get("a"); ok {
+		t.Error("got expired entry")
+	}
+}
+
+func TestRunInSandboxCached(t *testing.T) {
+	mgr, prov := newTestManager(t, models.SpecMap{
+		"go":     {Image: "golang", FileName: "main.go", Cacheable: true},
+		"python": {Image: "python", FileName: "main.py"},
+	})
+	mgr.cache = newResultCache(time.Minute, 0)
+	prov.digests = map[string]string{"golang": "sha256:1"}
+
+	run := func(lang, code string) *RunResult {
+		t.Helper()
+		output := NewOutputCollector(1024)
+		res, err := mgr.RunInSandbox(context.Background(), &models.ExecutionRequest{Language: lang, Code: code}, RunChannels{
+			Stdout: output.Stdout,
+			Stderr: output.Stderr,
+		})
+		output.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	expectCreated := func(expected int32) {
+		t.Helper()
+		if n := prov.created.Load(); n != expected {
+			t.Errorf("created %d sandboxes (expected: %d)", n, expected)
+		}
+	}
+
+	if res := run("go", "code"); res.Cached {
+		t.Error("first result is cached")
+	}
+	if res := run("go", "code"); !res.Cached {
+		t.Error("second result is not cached")
+	}
+	expectCreated(1)
+
+	run("go", "other code")
+	expectCreated(2)
+
+	run("python", "code")
+	run("python", "code")
+	expectCreated(4)
+
+	// Pulling a new image invalidates the cached results.
+	prov.digests["golang"] = "sha256:2"
+	mgr.PrepareEnvironments(context.Background(), false)
+	if res := run("go", "code"); res.Cached {
+		t.Error("result is cached after the image has been updated")
+	}
+	expectCreated(5)
+}
@@ -0,0 +1,67 @@
+package sandbox
+
+import "github.com/ranna-go/ranna/pkg/models"
+
+// Phase identifies a phase transition
+// of an execution.
+type Phase int
+
+const (
+	PhaseBuildStart Phase = iota
+	PhaseBuildEnd
+	PhaseRunStart
+)
+
+// PhaseEvent is passed when an execution with
+// a build phase transitions between its phases.
+//
+// Build is only set on PhaseBuildEnd.
+type PhaseEvent struct {
+	Phase Phase
+	RunId string
+	Build *BuildResult
+}
+
+// ToModel returns the phase transition as
+// event model with the given nonce.
+func (t PhaseEvent) ToModel(nonce int) models.Event {
+	runId := models.DataRunId{RunId: t.RunId}
+	switch t.Phase {
+	case PhaseBuildStart:
+		return models.Event{Code: models.EventBuildStart, Nonce: nonce, Data: runId}
+	case PhaseBuildEnd:
+		data := models.DataBuildEnd{DataRunId: runId}
+		if t.Build != nil {
+			data.BuildResponse = *t.Build.ToModel()
+		}
+		return models.Event{Code: models.EventBuildEnd, Nonce: nonce, Data: data}
+	default:
+		return models.Event{Code: models.EventRunStart, Nonce: nonce, Data: runId}
+	}
+}
+
+// RunChannels bundles the channels the events of
+// an execution are passed into.
+//
+// Queued receives the position of the execution in the
+// queue while it is waiting for a free slot. Spawn
+// receives the run ID of the execution as soon as
+// the sandbox has been created. Stdout and Stderr
+// receive the output of the run phase and must be set.
+// Stats periodically receives the resource usage of
+// the run phase. All other channels are not used if
+// they are nil.
+type RunChannels struct {
+	Queued chan int
+	Spawn  chan string
+	Stdout chan []byte
+	Stderr chan []byte
+	Phase  chan PhaseEvent
+	Stats  chan models.ResourceUsage
+}
+
+func (t RunChannels) sendPhase(evt PhaseEvent) {
+	if t.Phase != nil {
+		t.Phase <- evt
+	}
+}
@@ -0,0 +1,19 @@
+package sandbox
+
+import "context"
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx carrying the
+// identity of the client requesting an execution,
+// i.e. its IP address.
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client identity
+// passed with ctx or an empty string if not set.
+func ClientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -12,8 +13,12 @@ import (
 	"github.com/rs/xid"
 	"github.com/zekrotja/rogu"
 	"github.com/zekrotja/rogu/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/tracing"
 	"github.com/ranna-go/ranna/internal/util"
 	"github.com/ranna-go/ranna/pkg/models"
 )
@@ -22,10 +27,14 @@ const (
 	containerRootPath = "/var/tmp/exec"
 )
 
+var tracer = otel.Tracer("github.com/ranna-go/ranna/internal/sandbox/docker")
+
 type Provider struct {
 	cfg    ConfigProvider
 	logger rogu.Logger
 	client *client.Client
+
+	seccompProfile string
 }
 
 func NewProvider(cfg ConfigProvider) (t *Provider, err error) {
@@ -39,6 +48,16 @@ func NewProvider(cfg ConfigProvider) (t *Provider, err error) {
 		return nil, err
 	}
 
+	// The Docker API expects the seccomp profile
+	// content instead of a file path.
+	if profile := cfg.Config().Sandbox.Hardening.SeccompProfile; profile != "" {
+		data, err := os.ReadFile(profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading seccomp profile: %w", err)
+		}
+		t.seccompProfile = string(data)
+	}
+
 	return t, nil
 }
 
@@ -55,25 +74,55 @@ func (t *Provider) Info(ctx context.Context) (v *models.SandboxInfo, err error)
 }
 
 func (t *Provider) Prepare(ctx context.Context, spec models.Spec, force bool) (err error) {
+	ctx, span := tracer.Start(ctx, "docker.Prepare",
+		trace.WithAttributes(attribute.String("image", spec.Image), attribute.Bool("force", force)))
+	defer func() { tracing.End(span, err) }()
+
 	repo, tag := getImage(spec.Image)
 
 	if !force {
 		t.logger.Debug().Fields("image", spec.Image).Msg("inspecting image")
-		_, err = t.client.ImageInspect(ctx, spec.Image)
+		inspectCtx, inspectSpan := tracer.Start(ctx, "docker.ImageInspect")
+		_, err = t.client.ImageInspect(inspectCtx, spec.Image)
+		// A failed inspect only means that the
+		// image has to be pulled.
+		inspectSpan.SetAttributes(attribute.Bool("found", err == nil))
+		inspectSpan.End()
 		if err == nil {
 			return nil
 		}
 	}
 
 	t.logger.Info().Fields("repo", repo, "tag", tag).Msg("pull image")
-	resp, err := t.client.ImagePull(ctx, spec.Image, client.ImagePullOptions{})
+	return t.pull(ctx, spec.Image)
+}
+
+// pull pulls the given image and waits
+// until the pull has finished.
+func (t *Provider) pull(ctx context.Context, image string) (err error) {
+	ctx, span := tracer.Start(ctx, "docker.ImagePull")
+	defer func() { tracing.End(span, err) }()
+
+	resp, err := t.client.ImagePull(ctx, image, client.ImagePullOptions{})
 	if err != nil {
 		return err
 	}
 	return resp.Wait(ctx)
 }
 
+func (t *Provider) ImageDigest(ctx context.Context, image string) (string, error) {
+	res, err := t.client.ImageInspect(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	return res.ID, nil
+}
+
 func (t *Provider) CreateSandbox(ctx context.Context, spec sandbox.RunSpec) (sbx sandbox.Sandbox, err error) {
+	ctx, span := tracer.Start(ctx, "docker.CreateSandbox",
+		trace.WithAttributes(attribute.String("image", spec.Image)))
+	defer func() { tracing.End(span, err) }()
+
 	repo, tag := getImage(spec.Image)
 
 	err = t.Prepare(ctx, spec.Spec, false)
@@ -89,6 +138,13 @@ func (t *Provider) CreateSandbox(ctx context.Context, spec sandbox.RunSpec) (sbx
 		Cmd:             spec.GetCommandWithArgs(),
 		Env:             spec.GetEnv(),
 		NetworkDisabled: !t.cfg.Config().Sandbox.EnableNetworking,
+		AttachStdin:     true,
+		OpenStdin:       true,
+		StdinOnce:       true,
+	}
+
+	if spec.UID != 0 || spec.GID != 0 {
+		ctnCfg.User = fmt.Sprintf("%d:%d", spec.UID, spec.GID)
 	}
 
 	hostDir, err := filepath.Abs(spec.GetAssembledHostDir())
@@ -101,24 +157,86 @@ func (t *Provider) CreateSandbox(ctx context.Context, spec sandbox.RunSpec) (sbx
 		Runtime: t.cfg.Config().Sandbox.Runtime,
 	}
 
-	hostCfg.Memory, err = util.ParseMemoryStr(t.cfg.Config().Sandbox.Memory)
-	if err != nil {
+	applyLimits(hostCfg, spec.Limits)
+
+	if err = t.applyHardening(hostCfg, spec.Hardening); err != nil {
 		return nil, err
 	}
-	hostCfg.MemorySwap = hostCfg.Memory
 
-	container, err := t.client.ContainerCreate(ctx, client.ContainerCreateOptions{
+	createCtx, createSpan := tracer.Start(ctx, "docker.ContainerCreate")
+	container, err := t.client.ContainerCreate(createCtx, client.ContainerCreateOptions{
 		Config:     ctnCfg,
 		HostConfig: hostCfg,
 		Name:       fmt.Sprintf("ranna-%s-%s", spec.Language, xid.New().String()),
 	})
+	tracing.End(createSpan, err)
+	if err != nil {
+		return nil, err
+	}
 	t.logger.Debug().Fields("spec", spec.Image, "id", container.ID).Msg("container created")
+	span.SetAttributes(attribute.String("id", container.ID))
 
 	sbx = newSandbox(t.client, &container)
 
 	return sbx, nil
 }
 
+func applyLimits(hostCfg *container.HostConfig, limits sandbox.ResourceLimits) {
+	hostCfg.Memory = limits.Memory
+	hostCfg.MemorySwap = limits.Memory
+	hostCfg.NanoCPUs = limits.NanoCPUs
+	hostCfg.CPUShares = limits.CPUShares
+	hostCfg.CpusetCpus = limits.CPUSetCPUs
+
+	if limits.PidsLimit > 0 {
+		hostCfg.PidsLimit = &limits.PidsLimit
+	}
+
+	addUlimit := func(name string, v int64) {
+		if v > 0 {
+			hostCfg.Ulimits = append(hostCfg.Ulimits, &container.Ulimit{Name: name, Soft: v, Hard: v})
+		}
+	}
+	addUlimit("nofile", limits.NoFile)
+	addUlimit("fsize", limits.FSize)
+	addUlimit("nproc", limits.NProc)
+}
+
+func (t *Provider) applyHardening(hostCfg *container.HostConfig, optOut *models.HardeningSpec) error {
+	cfg := t.cfg.Config().Sandbox.Hardening
+	if optOut == nil {
+		optOut = &models.HardeningSpec{}
+	}
+
+	if cfg.ReadOnlyRootFS && !optOut.WritableRootFS {
+		tmpfsSize, err := util.ParseMemoryStr(cfg.TmpfsSize)
+		if err != nil {
+			return err
+		}
+		tmpfsOpts := "rw,nosuid,nodev,mode=1777"
+		if tmpfsSize > 0 {
+			tmpfsOpts += fmt.Sprintf(",size=%d", tmpfsSize)
+		}
+		hostCfg.ReadonlyRootfs = true
+		hostCfg.Tmpfs = map[string]string{"/tmp": tmpfsOpts}
+	}
+
+	if cfg.DropCapabilities {
+		hostCfg.CapDrop = []string{"ALL"}
+	}
+	hostCfg.CapAdd = optOut.CapAdd
+
+	if cfg.NoNewPrivileges && !optOut.AllowNewPrivileges {
+		hostCfg.SecurityOpt = append(hostCfg.SecurityOpt, "no-new-privileges")
+	}
+
+	if t.seccompProfile != "" && !optOut.DefaultSeccomp {
+		hostCfg.SecurityOpt = append(hostCfg.SecurityOpt, "seccomp="+t.seccompProfile)
+	}
+
+	return nil
+}
+
 func getImage(environmentDescriptor string) (repo, tag string) {
 	split := strings.SplitN(environmentDescriptor, ":", 2)
 	if len(split) == 1 {
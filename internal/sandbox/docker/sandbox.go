@@ -2,12 +2,26 @@ package docker
 
 import (
 	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/tracing"
 	"github.com/ranna-go/ranna/pkg/chanwriter"
+	"github.com/ranna-go/ranna/pkg/models"
 	"github.com/zekrotja/rogu"
 	"github.com/zekrotja/rogu/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	stopTimeout = 10 * time.Second
 )
 
 // Sandbox implements Sandbox for
@@ -16,6 +30,13 @@ type Sandbox struct {
 	logger    rogu.Logger
 	client    *client.Client
 	container *client.ContainerCreateResult
+	killed    atomic.Bool
+
+	stdinMtx     sync.Mutex
+	stdinConn    io.WriteCloser
+	stdinPending []byte
+	stdinClosed  bool
+	stdinDone    bool
 }
 
 func newSandbox(client *client.Client, container *client.ContainerCreateResult) *Sandbox {
@@ -30,48 +51,139 @@ func (t *Sandbox) ID() string {
 	return t.container.ID
 }
 
-func (t *Sandbox) Run(ctx context.Context, cOut, cErr chan []byte) (err error) {
+func (t *Sandbox) Run(
+	ctx context.Context,
+	cOut, cErr chan []byte,
+	cStats chan models.ResourceUsage,
+) (res *sandbox.RunResult, err error) {
+	ctx, span := t.startSpan(ctx, "docker.Run")
+	defer func() { tracing.End(span, err) }()
+
 	buffStdout := chanwriter.New(cOut)
 	buffStderr := chanwriter.New(cErr)
-	res, err := t.client.ContainerAttach(ctx, t.container.ID, client.ContainerAttachOptions{
+	attachCtx, attachSpan := t.startSpan(ctx, "docker.ContainerAttach")
+	attach, err := t.client.ContainerAttach(attachCtx, t.container.ID, client.ContainerAttachOptions{
+		Stdin:  true,
 		Stdout: true,
 		Stderr: true,
 		Stream: true,
 	})
+	tracing.End(attachSpan, err)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer res.Close()
+	defer func() {
+		attach.Close()
+		t.stdinMtx.Lock()
+		t.stdinConn = nil
+		t.stdinClosed = true
+		t.stdinDone = true
+		t.stdinMtx.Unlock()
+	}()
 	t.logger.Debug().Fields("id", t.container.ID).Msg("container attached")
 
-	cErrStdCopy := make(chan error)
+	go t.attachStdin(stdinConn{&attach.HijackedResponse})
+
+	cErrStdCopy := make(chan error, 1)
 
 	go func() {
-		_, err := stdcopy.StdCopy(buffStdout, buffStderr, res.Reader)
+		_, err := stdcopy.StdCopy(buffStdout, buffStderr, attach.Reader)
 		if err != nil {
 			t.logger.Debug().Err(err).Msg("failed copying stdin/stdout")
 			cErrStdCopy <- err
 		}
 	}()
 
-	_, err = t.client.ContainerStart(ctx, t.container.ID, client.ContainerStartOptions{})
+	startCtx, startSpan := t.startSpan(ctx, "docker.ContainerStart")
+	_, err = t.client.ContainerStart(startCtx, t.container.ID, client.ContainerStartOptions{})
+	tracing.End(startSpan, err)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	t.logger.Debug().Fields("id", t.container.ID).Msg("container started")
 
-	wait := t.client.ContainerWait(ctx, t.container.ID, client.ContainerWaitOptions{})
+	stats := &statsCollector{}
+	statsCtx, cancelStats := context.WithCancel(ctx)
+	cStatsDone := make(chan struct{})
+	go func() {
+		defer close(cStatsDone)
+		if sErr := stats.run(statsCtx, t.client, t.container.ID, cStats); sErr != nil {
+			t.logger.Debug().Err(sErr).Field("id", t.container.ID).Msg("failed collecting stats")
+		}
+	}()
+
+	waitCtx, waitSpan := t.startSpan(ctx, "docker.ContainerWait")
+	wait := t.client.ContainerWait(waitCtx, t.container.ID, client.ContainerWaitOptions{})
 	select {
 	case err = <-wait.Error:
 	case err = <-cErrStdCopy:
 	case <-wait.Result:
 	}
+	tracing.End(waitSpan, err)
+
+	cancelStats()
+	<-cStatsDone
 
 	t.logger.Debug().Fields("id", t.container.ID).Msg("container finished")
 
+	if ctx.Err() != nil {
+		// The run context has been canceled (i.e. because
+		// the execution timed out), so the container is
+		// stopped and its state is collected using a
+		// detached context.
+		err = context.Cause(ctx)
+		ctx = context.WithoutCancel(ctx)
+		if sErr := t.stop(ctx); sErr != nil {
+			t.logger.Error().Err(sErr).Field("id", t.container.ID).Msg("failed stopping container")
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	res, sErr := t.state(ctx)
+	if sErr != nil {
+		return nil, sErr
+	}
+	if usage, ok := stats.result(); ok {
+		res.Usage = &usage
+	}
+
+	return res, err
+}
+
+func (t *Sandbox) WriteStdin(p []byte) (err error) {
+	t.stdinMtx.Lock()
+	defer t.stdinMtx.Unlock()
+
+	if t.stdinClosed {
+		return sandbox.ErrStdinClosed
+	}
+
+	if t.stdinConn == nil {
+		t.stdinPending = append(t.stdinPending, p...)
+		return nil
+	}
+
+	_, err = t.stdinConn.Write(p)
 	return err
 }
 
+func (t *Sandbox) CloseStdin() error {
+	t.stdinMtx.Lock()
+	defer t.stdinMtx.Unlock()
+
+	if t.stdinClosed {
+		return sandbox.ErrStdinClosed
+	}
+
+	t.stdinClosed = true
+	if t.stdinConn == nil {
+		return nil
+	}
+
+	return t.stdinConn.Close()
+}
+
 func (t *Sandbox) IsRunning(ctx context.Context) (ok bool, err error) {
 	ctn, err := t.client.ContainerInspect(ctx, t.container.ID, client.ContainerInspectOptions{})
 	if err != nil {
@@ -82,12 +194,105 @@ func (t *Sandbox) IsRunning(ctx context.Context) (ok bool, err error) {
 	return
 }
 
-func (t *Sandbox) Kill(ctx context.Context) error {
-	_, err := t.client.ContainerKill(ctx, t.container.ID, client.ContainerKillOptions{})
+func (t *Sandbox) Kill(ctx context.Context) (err error) {
+	ctx, span := t.startSpan(ctx, "docker.ContainerKill")
+	defer func() { tracing.End(span, err) }()
+
+	t.killed.Store(true)
+	_, err = t.client.ContainerKill(ctx, t.container.ID, client.ContainerKillOptions{})
+	return err
+}
+
+func (t *Sandbox) Delete(ctx context.Context) (err error) {
+	ctx, span := t.startSpan(ctx, "docker.ContainerRemove")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = t.client.ContainerRemove(ctx, t.container.ID, client.ContainerRemoveOptions{})
 	return err
 }
 
-func (t *Sandbox) Delete(ctx context.Context) error {
-	_, err := t.client.ContainerRemove(ctx, t.container.ID, client.ContainerRemoveOptions{})
+// startSpan starts a span with the given name
+// annotated with the ID of the container.
+func (t *Sandbox) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithAttributes(attribute.String("container.id", t.container.ID)))
+}
+
+// stop kills the container without flagging it as
+// killed and blocks until it is no more running.
+func (t *Sandbox) stop(ctx context.Context) (err error) {
+	if _, err = t.client.ContainerKill(ctx, t.container.ID, client.ContainerKillOptions{}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, stopTimeout)
+	defer cancel()
+
+	wait := t.client.ContainerWait(ctx, t.container.ID, client.ContainerWaitOptions{
+		Condition: container.WaitConditionNotRunning,
+	})
+	select {
+	case err = <-wait.Error:
+	case <-wait.Result:
+	}
+
 	return err
 }
+
+// state inspects the container and returns its
+// final state as RunResult.
+func (t *Sandbox) state(ctx context.Context) (res *sandbox.RunResult, err error) {
+	ctn, err := t.client.ContainerInspect(ctx, t.container.ID, client.ContainerInspectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	res = &sandbox.RunResult{
+		ExitCode:  ctn.Container.State.ExitCode,
+		OOMKilled: ctn.Container.State.OOMKilled,
+		Killed:    t.killed.Load(),
+	}
+
+	return res, nil
+}
+
+// attachStdin sets conn as stdin stream of the
+// sandbox and writes all input which has been passed
+// before the container has been attached. If stdin
+// has been closed in the meantime, conn is closed
+// afterwards.
+func (t *Sandbox) attachStdin(conn io.WriteCloser) {
+	t.stdinMtx.Lock()
+	defer t.stdinMtx.Unlock()
+
+	if t.stdinDone {
+		return
+	}
+	t.stdinConn = conn
+
+	var err error
+	if len(t.stdinPending) != 0 {
+		_, err = conn.Write(t.stdinPending)
+		t.stdinPending = nil
+	}
+	if err == nil && t.stdinClosed {
+		err = conn.Close()
+	}
+	if err != nil {
+		t.logger.Debug().Err(err).Field("id", t.container.ID).Msg("failed writing stdin")
+	}
+}
+
+// stdinConn wraps a hijacked connection so that
+// Close only closes the write side of it.
+type stdinConn struct {
+	*client.HijackedResponse
+}
+
+func (t stdinConn) Write(p []byte) (int, error) {
+	return t.Conn.Write(p)
+}
+
+func (t stdinConn) Close() error {
+	return t.CloseWrite()
+}
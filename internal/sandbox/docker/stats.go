@@ -0,0 +1,148 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// statsCollector aggregates the resource usage
+// of a container from the stats stream of the
+// Docker API.
+type statsCollector struct {
+	mtx     sync.Mutex
+	usage   models.ResourceUsage
+	samples int
+}
+
+// collect reads the stats stream of the container
+// with the given ID until ctx is canceled or the
+// stream ends. If cStats is not nil, the aggregated
+// usage is written into it after each sample.
+func (t *statsCollector) collect(
+	ctx context.Context,
+	cl *client.Client,
+	id string,
+	cStats chan models.ResourceUsage,
+) error {
+	stats, err := cl.ContainerStats(ctx, id, client.ContainerStatsOptions{Stream: true})
+	if err != nil {
+		return err
+	}
+	defer stats.Body.Close()
+
+	dec := json.NewDecoder(stats.Body)
+	for {
+		var sample container.StatsResponse
+		if err = dec.Decode(&sample); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		// Samples of stopped containers are empty.
+		if sample.Read.IsZero() {
+			continue
+		}
+
+		usage := t.add(&sample)
+		if cStats != nil {
+			select {
+			case cStats <- usage:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// run takes a single sample of the container with the
+// given ID and then collects its stats stream until ctx
+// is canceled or the stream ends. The stream provides
+// about one sample per second and the samples of stopped
+// containers are empty, so the single sample accounts runs
+// which finish before the stream has sampled them. It is
+// not canceled with ctx so that it is not lost if the
+// container finishes right away.
+func (t *statsCollector) run(
+	ctx context.Context,
+	cl *client.Client,
+	id string,
+	cStats chan models.ResourceUsage,
+) error {
+	sErr := t.sample(context.WithoutCancel(ctx), cl, id)
+	err := t.collect(ctx, cl, id, cStats)
+	return errors.Join(sErr, err)
+}
+
+// sample takes a single sample of the container
+// with the given ID.
+func (t *statsCollector) sample(ctx context.Context, cl *client.Client, id string) error {
+	stats, err := cl.ContainerStats(ctx, id, client.ContainerStatsOptions{})
+	if err != nil {
+		return err
+	}
+	defer stats.Body.Close()
+
+	var sample container.StatsResponse
+	if err = json.NewDecoder(stats.Body).Decode(&sample); err != nil {
+		return err
+	}
+	if !sample.Read.IsZero() {
+		t.add(&sample)
+	}
+
+	return nil
+}
+
+// result returns the aggregated usage. ok is false
+// if no sample has been collected.
+func (t *statsCollector) result() (usage models.ResourceUsage, ok bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.usage, t.samples != 0
+}
+
+func (t *statsCollector) add(sample *container.StatsResponse) models.ResourceUsage {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.samples++
+
+	// Like the Docker CLI, the inactive file cache is
+	// not accounted as used memory.
+	mem := sample.MemoryStats.Usage
+	inactive, ok := sample.MemoryStats.Stats["inactive_file"]
+	if !ok {
+		inactive = sample.MemoryStats.Stats["total_inactive_file"]
+	}
+	if inactive < mem {
+		mem -= inactive
+	}
+	t.usage.PeakMemoryBytes = max(t.usage.PeakMemoryBytes, int64(mem), int64(sample.MemoryStats.MaxUsage))
+
+	t.usage.CPUTimeMS = max(t.usage.CPUTimeMS, int64(sample.CPUStats.CPUUsage.TotalUsage/1e6))
+	t.usage.PeakPids = max(t.usage.PeakPids, int64(sample.PidsStats.Current))
+
+	var read, write int64
+	for _, e := range sample.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			read += int64(e.Value)
+		case "write":
+			write += int64(e.Value)
+		}
+	}
+	t.usage.BlockIOReadBytes = max(t.usage.BlockIOReadBytes, read)
+	t.usage.BlockIOWriteBytes = max(t.usage.BlockIOWriteBytes, write)
+
+	return t.usage
+}
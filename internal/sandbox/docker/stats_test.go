@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+func TestStatsCollectorAdd(t *testing.T) {
+	sample := func(mem, inactive, cpu, pids, read, write uint64) *container.StatsResponse {
+		return &container.StatsResponse{
+			MemoryStats: container.MemoryStats{
+				Usage: mem,
+				Stats: map[string]uint64{"inactive_file": inactive},
+			},
+			CPUStats: container.CPUStats{
+				CPUUsage: container.CPUUsage{TotalUsage: cpu},
+			},
+			PidsStats: container.PidsStats{Current: pids},
+			BlkioStats: container.BlkioStats{
+				IoServiceBytesRecursive: []container.BlkioStatEntry{
+					{Op: "read", Value: read},
+					{Op: "Write", Value: write},
+				},
+			},
+		}
+	}
+
+	var c statsCollector
+	c.add(sample(100, 20, 5e6, 3, 10, 0))
+	c.add(sample(50, 0, 12e6, 1, 10, 30))
+
+	exp := models.ResourceUsage{
+		PeakMemoryBytes:   80,
+		CPUTimeMS:         12,
+		BlockIOReadBytes:  10,
+		BlockIOWriteBytes: 30,
+		PeakPids:          3,
+	}
+	if usage, ok := c.result(); !ok || usage != exp {
+		t.Errorf("usage was %+v (expected: %+v)", usage, exp)
+	}
+}
+
+func TestStatsCollectorShortRun(t *testing.T) {
+	// Like the Docker daemon, the fake daemon only returns
+	// filled samples while the container is running. The
+	// container finishes before the first sample of the
+	// stream, which only contains an empty sample.
+	var running atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/containers/ctn/stats") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("stream") != "false" {
+			running.Store(false)
+		}
+		var sample container.StatsResponse
+		if running.Load() {
+			sample.Read = time.Now()
+			sample.CPUStats.CPUUsage.TotalUsage = 30e6
+		}
+		json.NewEncoder(w).Encode(sample)
+	}))
+	defer srv.Close()
+
+	cl, err := client.New(client.WithHost("tcp://"+srv.Listener.Addr().String()), client.WithAPIVersion("1.47"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c statsCollector
+	if err = c.sample(context.Background(), cl, "ctn"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.result(); ok {
+		t.Fatal("empty sample of stopped container has been collected")
+	}
+
+	running.Store(true)
+	c.run(context.Background(), cl, "ctn", nil)
+	if usage, ok := c.result(); !ok || usage.CPUTimeMS != 30 {
+		t.Errorf("invalid usage of short run: %+v", usage)
+	}
+}
@@ -1,6 +1,8 @@
 package sandbox
 
 import (
+	"io/fs"
+
 	"github.com/ranna-go/ranna/internal/config"
 	"github.com/ranna-go/ranna/internal/spec"
 )
@@ -12,7 +14,9 @@ type SpecProvider interface {
 type FileProvider interface {
 	CreateDirectory(path string) error
 	CreateFileWithContent(path, content string) error
+	ChownDirectory(path string, uid, gid int) error
 	DeleteDirectory(path string) error
+	OpenDirectory(path string, fn func(fsys fs.FS) error) error
 }
 
 type ConfigProvider interface {
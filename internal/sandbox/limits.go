@@ -0,0 +1,156 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// minCPUs is the smallest CPU limit accepted by Docker.
+const minCPUs = 0.01
+
+var (
+	errInvalidLimit  = errors.New("invalid resource limit")
+	errLimitExceeded = errors.New("resource limit exceeded")
+)
+
+// ResourceLimits wraps the resource constraints
+// applied to a sandbox. For all fields except the
+// timeouts, a value of 0 means that the constraint
+// is not applied. The timeouts are always applied.
+type ResourceLimits struct {
+	Memory       int64         `json:"memory,omitempty" yaml:"memory,omitempty"`
+	NanoCPUs     int64         `json:"nanocpus,omitempty" yaml:"nanocpus,omitempty"`
+	CPUShares    int64         `json:"cpushares,omitempty" yaml:"cpushares,omitempty"`
+	CPUSetCPUs   string        `json:"cpusetcpus,omitempty" yaml:"cpusetcpus,omitempty"`
+	PidsLimit    int64         `json:"pidslimit,omitempty" yaml:"pidslimit,omitempty"`
+	NoFile       int64         `json:"nofile,omitempty" yaml:"nofile,omitempty"`
+	FSize        int64         `json:"fsize,omitempty" yaml:"fsize,omitempty"`
+	NProc        int64         `json:"nproc,omitempty" yaml:"nproc,omitempty"`
+	Timeout      time.Duration `json:"-" yaml:"-"`
+	BuildTimeout time.Duration `json:"-" yaml:"-"`
+}
+
+// resolveLimits returns the resource limits for the
+// given request and spec. Limits which are not specified
+// in the request are taken from the spec or, if not set
+// there, from the config. Limits specified in the request
+// are validated against the maximums set in the config.
+// The timeout of the build phase can only be set by the
+// spec.
+//
+// If no maximum is configured, the default value acts
+// as maximum. A value of 0 means no limit for the
+// resource constraints. The timeouts are always applied,
+// so a timeout of 0 lets the execution time out at once.
+func (t *Manager) resolveLimits(req *models.ExecutionRequest, spc models.Spec) (l ResourceLimits, err error) {
+	cfg := t.cfg.Config().Sandbox
+
+	memory, err := util.ParseMemoryStr(cfg.Memory)
+	if err != nil {
+		return l, SystemError{err}
+	}
+	maxMemory := memory
+	if cfg.MaxMemory != "" {
+		if maxMemory, err = util.ParseMemoryStr(cfg.MaxMemory); err != nil {
+			return l, SystemError{err}
+		}
+	}
+
+	timeoutSeconds := cfg.TimeoutSeconds
+	maxTimeoutSeconds := cfg.TimeoutSeconds
+	if cfg.MaxTimeoutSeconds > 0 {
+		maxTimeoutSeconds = cfg.MaxTimeoutSeconds
+	}
+	if spc.Run != nil && spc.Run.TimeoutSeconds > 0 {
+		timeoutSeconds = spc.Run.TimeoutSeconds
+	}
+
+	buildTimeoutSeconds := cfg.TimeoutSeconds
+	if spc.Build != nil && spc.Build.TimeoutSeconds > 0 {
+		buildTimeoutSeconds = spc.Build.TimeoutSeconds
+	}
+
+	cpus := cfg.CPUs
+
+	l.CPUShares = cfg.CPUShares
+	l.CPUSetCPUs = cfg.CPUSetCPUs
+	l.PidsLimit = cfg.PidsLimit
+	l.NoFile = cfg.Ulimits.NoFile
+	l.NProc = cfg.Ulimits.NProc
+	if l.FSize, err = util.ParseMemoryStr(cfg.Ulimits.FSize); err != nil {
+		return l, SystemError{err}
+	}
+
+	if res := spc.Resources; res != nil {
+		if res.CPUs > 0 {
+			cpus = res.CPUs
+		}
+		if res.CPUShares > 0 {
+			l.CPUShares = res.CPUShares
+		}
+		if res.CPUSetCPUs != "" {
+			l.CPUSetCPUs = res.CPUSetCPUs
+		}
+		if res.PidsLimit > 0 {
+			l.PidsLimit = res.PidsLimit
+		}
+		if ul := res.Ulimits; ul != nil {
+			if ul.NoFile > 0 {
+				l.NoFile = ul.NoFile
+			}
+			if ul.NProc > 0 {
+				l.NProc = ul.NProc
+			}
+			if ul.FSize != "" {
+				if l.FSize, err = util.ParseMemoryStr(ul.FSize); err != nil {
+					return l, SystemError{err}
+				}
+			}
+		}
+	}
+
+	maxCPUs := cpus
+	if cfg.MaxCPUs > 0 {
+		maxCPUs = cfg.MaxCPUs
+	}
+
+	if req.Memory != "" {
+		if memory, err = util.ParseMemoryStr(req.Memory); err != nil || memory <= 0 {
+			return l, fmt.Errorf("%w: memory: %s", errInvalidLimit, req.Memory)
+		}
+		if maxMemory > 0 && memory > maxMemory {
+			return l, fmt.Errorf("%w: memory must not exceed %d bytes", errLimitExceeded, maxMemory)
+		}
+	}
+
+	if req.TimeoutSeconds < 0 {
+		return l, fmt.Errorf("%w: timeout_seconds: %d", errInvalidLimit, req.TimeoutSeconds)
+	}
+	if req.TimeoutSeconds > 0 {
+		timeoutSeconds = req.TimeoutSeconds
+		if maxTimeoutSeconds > 0 && timeoutSeconds > maxTimeoutSeconds {
+			return l, fmt.Errorf("%w: timeout_seconds must not exceed %d", errLimitExceeded, maxTimeoutSeconds)
+		}
+	}
+
+	if req.CPUs < 0 || (req.CPUs > 0 && req.CPUs < minCPUs) {
+		return l, fmt.Errorf("%w: cpus: %g", errInvalidLimit, req.CPUs)
+	}
+	if req.CPUs > 0 {
+		cpus = req.CPUs
+		if maxCPUs > 0 && cpus > maxCPUs {
+			return l, fmt.Errorf("%w: cpus must not exceed %g", errLimitExceeded, maxCPUs)
+		}
+	}
+
+	l.Memory = memory
+	l.NanoCPUs = int64(cpus * 1e9)
+	l.Timeout = time.Duration(timeoutSeconds) * time.Second
+	l.BuildTimeout = time.Duration(buildTimeoutSeconds) * time.Second
+
+	return l, nil
+}
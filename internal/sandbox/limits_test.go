@@ -0,0 +1,81 @@
+package sandbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type staticConfig struct {
+	cfg *config.Config
+}
+
+func (t staticConfig) Config() *config.Config {
+	return t.cfg
+}
+
+func TestResolveLimits(t *testing.T) {
+	mgr := &Manager{cfg: staticConfig{&config.Config{
+		Sandbox: config.Sandbox{
+			Memory:            "100M",
+			MaxMemory:         "1G",
+			TimeoutSeconds:    20,
+			MaxTimeoutSeconds: 60,
+			CPUs:              0.5,
+			MaxCPUs:           2,
+			PidsLimit:         64,
+			Ulimits: config.Ulimits{
+				NoFile: 1024,
+				FSize:  "10M",
+			},
+		},
+	}}}
+
+	l, err := mgr.resolveLimits(&models.ExecutionRequest{}, models.Spec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Memory != 100*1024*1024 || l.Timeout != 20*time.Second || l.NanoCPUs != 5e8 {
+		t.Errorf("invalid default limits: %+v", l)
+	}
+
+	l, err = mgr.resolveLimits(&models.ExecutionRequest{Memory: "1G", TimeoutSeconds: 60, CPUs: 2}, models.Spec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Memory != 1024*1024*1024 || l.Timeout != 60*time.Second || l.NanoCPUs != 2e9 {
+		t.Errorf("invalid request limits: %+v", l)
+	}
+
+	l, err = mgr.resolveLimits(&models.ExecutionRequest{}, models.Spec{
+		Resources: &models.ResourceSpec{
+			CPUs:      1,
+			PidsLimit: 128,
+			Ulimits:   &models.UlimitSpec{NProc: 32},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.NanoCPUs != 1e9 || l.PidsLimit != 128 || l.NoFile != 1024 ||
+		l.FSize != 10*1024*1024 || l.NProc != 32 {
+		t.Errorf("invalid spec limits: %+v", l)
+	}
+
+	expectErr := func(req *models.ExecutionRequest, expErr error) {
+		if _, err := mgr.resolveLimits(req, models.Spec{}); !errors.Is(err, expErr) {
+			t.Errorf("error was %v (expected: %v)", err, expErr)
+		}
+	}
+
+	expectErr(&models.ExecutionRequest{Memory: "2G"}, errLimitExceeded)
+	expectErr(&models.ExecutionRequest{TimeoutSeconds: 61}, errLimitExceeded)
+	expectErr(&models.ExecutionRequest{CPUs: 2.5}, errLimitExceeded)
+	expectErr(&models.ExecutionRequest{Memory: "poggers"}, errInvalidLimit)
+	expectErr(&models.ExecutionRequest{TimeoutSeconds: -1}, errInvalidLimit)
+	expectErr(&models.ExecutionRequest{CPUs: -1}, errInvalidLimit)
+	expectErr(&models.ExecutionRequest{CPUs: 0.001}, errInvalidLimit)
+}
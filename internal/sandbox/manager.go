@@ -4,14 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/zekrotja/rogu"
 	"github.com/zekrotja/rogu/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/ranna-go/ranna/internal/metrics"
+	"github.com/ranna-go/ranna/internal/tracing"
+	"github.com/ranna-go/ranna/internal/util"
 	"github.com/ranna-go/ranna/pkg/models"
 )
 
@@ -19,8 +28,13 @@ var (
 	errUnsupportedLanguage        = errors.New("unsupported language spec")
 	errNoInlineExpressionsSupport = errors.New("this spec has no support for inline expressions")
 	errTimedOut                   = errors.New("code execution timed out")
+	errInvalidFilePath            = errors.New("invalid file path")
+	errMissingEntryFile           = errors.New("code is empty and no entry point file has been passed")
+	errConflictingEntryFile       = errors.New("entry point file is passed as code and as file")
 )
 
+var tracer = otel.Tracer("github.com/ranna-go/ranna/internal/sandbox")
+
 // Manager is a higher level abstraction used to create and
 // run sandboxes, prepare the environment for given specs and
 // cleaning up running containers on teardown.
@@ -33,13 +47,26 @@ type Manager struct {
 	logger  rogu.Logger
 
 	runningSandboxes *sync.Map
+	pool             *pool
+	queue            *queue
+	cache            *resultCache
+	digests          *sync.Map
 }
 
-// sandboxWrapper wraps a sandbox instance and
-// the used hostDir.
+// sandboxWrapper wraps a sandbox instance, the
+// ID of the execution it belongs to, the key of
+// its spec and the used hostDir. If hostDir is
+// empty, it is not deleted on cleanup.
+//
+// The client identity and the start time are
+// set when the sandbox is run.
 type sandboxWrapper struct {
 	Sandbox
+	runId   string
+	spec    string
 	hostDir string
+	client  string
+	started time.Time
 }
 
 // SystemError wraps an error occuring with the
@@ -55,6 +82,46 @@ func IsSystemError(err error) (ok bool) {
 	return errors.As(err, &systemError)
 }
 
+// RejectedError wraps an error returned when an
+// execution has been rejected before a sandbox has
+// been spawned, i.e. because the request is invalid
+// or the server is at its capacity.
+type RejectedError struct {
+	error
+}
+
+// NewRejectedError returns err wrapped
+// as RejectedError.
+func NewRejectedError(err error) RejectedError {
+	return RejectedError{err}
+}
+
+func (t RejectedError) Unwrap() error {
+	return t.error
+}
+
+// IsRejectedError returns true when the passed
+// error is type of RejectedError.
+func IsRejectedError(err error) bool {
+	var rejectedError RejectedError
+	return errors.As(err, &rejectedError)
+}
+
+// ErrorModel returns err as ErrorModel with the
+// HTTP status code matching the type of err.
+func ErrorModel(err error) *models.ErrorModel {
+	code := http.StatusBadRequest
+	if IsSystemError(err) {
+		code = http.StatusInternalServerError
+	} else if IsUnavailableError(err) {
+		code = http.StatusServiceUnavailable
+	}
+	return &models.ErrorModel{
+		Error: err.Error(),
+		Code:  code,
+	}
+}
+
 // NewManager returns a new instance of ManagerImpl.
 func NewManager(
 	sandbox Provider,
@@ -73,6 +140,23 @@ func NewManager(
 	t.logger = log.Tagged("Manager")
 
 	t.runningSandboxes = &sync.Map{}
+	t.pool = newPool(t)
+
+	queueCfg := cfg.Config().Sandbox.Queue
+	t.queue = newQueue(
+		queueCfg.MaxConcurrent,
+		queueCfg.MaxLength,
+		time.Duration(queueCfg.TimeoutSeconds)*time.Second)
+
+	cacheCfg := cfg.Config().Sandbox.Cache
+	var cacheMaxSize int64
+	if cacheCfg.MaxSize != "" {
+		if cacheMaxSize, err = util.ParseMemoryStr(cacheCfg.MaxSize); err != nil {
+			return nil, err
+		}
+	}
+	t.cache = newResultCache(time.Duration(cacheCfg.TTLSeconds)*time.Second, cacheMaxSize)
+	t.digests = &sync.Map{}
 
 	return t, nil
 }
@@ -84,37 +168,123 @@ func NewManager(
 //
 // If force is true, the environment is being prepared even
 // though it has been already prepared before. This is useful
-// to perform image updates, for example.
+// to perform image updates, for example. In this case, all
+// pooled sandboxes are re-created as well.
 func (t *Manager) PrepareEnvironments(ctx context.Context, force bool) (errs []error) {
 	errs = []error{}
 
 	for _, spec := range t.spec.Spec().GetSnapshot() {
-		if spec.Image == "" {
-			continue
-		}
-		if err := t.sandbox.Prepare(ctx, *spec, force); err != nil {
-			t.logger.Error().Field("image", spec.Image).Err(err).Msg("failed preparing env")
+		if err := t.prepare(ctx, *spec, force); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	if force {
+		t.pool.purge(ctx)
+	}
+
 	return errs
 }
 
+// PrepareEnvironment works like PrepareEnvironments
+// but only prepares the environment of the spec
+// resolved by the given language.
+func (t *Manager) PrepareEnvironment(ctx context.Context, language string, force bool) error {
+	spcKey, spc, ok := t.spec.Spec().Resolve(language)
+	if !ok {
+		return errUnsupportedLanguage
+	}
+
+	if err := t.prepare(ctx, spc, force); err != nil {
+		return err
+	}
+
+	if force {
+		t.pool.purge(ctx, spcKey)
+	}
+
+	return nil
+}
+
+// prepare prepares the environment of the given spec
+// and updates the digest of its image.
+func (t *Manager) prepare(ctx context.Context, spec models.Spec, force bool) error {
+	if spec.Image == "" {
+		return nil
+	}
+
+	err := t.sandbox.Prepare(ctx, spec, force)
+	metrics.ImagePulls.WithLabelValues(spec.Image, metrics.Result(err)).Inc()
+	if err != nil {
+		t.logger.Error().Field("image", spec.Image).Err(err).Msg("failed preparing env")
+		return err
+	}
+
+	// Cached results of executions using an outdated
+	// image must not be served anymore.
+	if t.cache.enabled() {
+		if err = t.updateImageDigest(ctx, spec.Image); err != nil {
+			t.logger.Error().Field("image", spec.Image).Err(err).Msg("failed getting image digest")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartPool fills the pools of pre-created sandboxes
+// of all specs with a pool size and keeps refilling
+// them in the background until ctx is canceled.
+func (t *Manager) StartPool(ctx context.Context) {
+	t.pool.start(ctx)
+}
+
+// PoolInfo returns the occupancy of the pools of
+// pre-created sandboxes by spec.
+func (t *Manager) PoolInfo() map[string]models.PoolInfo {
+	return t.pool.info()
+}
+
 // RunInSandbox tries to extract the desired spec
 // to be used defined by the req. Then, a new sandbox
 // is created with this spec and given runtime variables.
 // The sandbox is then started and the current go routine is
 // blocked until the execution is finished or timed out.
 //
-// On success returns an execution response.
+// If the spec defines a build phase, the build command
+// is executed in a separate sandbox before the run phase.
+// The run phase is skipped if the build did not succeed.
+// Both sandboxes are tracked by the same run ID.
+//
+// If the spec is cacheable and the result of an identical
+// request is cached, the cached result is returned without
+// creating a sandbox.
+//
+// If the number of concurrently running sandboxes is
+// limited, the execution is queued until a slot is
+// available. The client identity passed with ctx is
+// used to share the slots fairly between clients.
+//
+// On success returns the final state of the execution.
+// When the execution timed out, the state is returned
+// without an error and flagged as timed out.
 func (t *Manager) RunInSandbox(
 	ctx context.Context,
 	req *models.ExecutionRequest,
-	cSpn chan string,
-	cOut chan []byte,
-	cErr chan []byte,
-) (err error) {
+	chans RunChannels,
+) (res *RunResult, err error) {
+	ctx, span := tracer.Start(ctx, "sandbox.RunInSandbox",
+		trace.WithAttributes(attribute.String("language", req.Language)))
+	defer func() { tracing.End(span, err) }()
+
+	// Try to get spec from specified language
+	spcKey, spc, ok := t.spec.Spec().Resolve(req.Language)
+	if !ok {
+		metrics.Executions.WithLabelValues("", metrics.OutcomeInvalid).Inc()
+		return nil, RejectedError{errUnsupportedLanguage}
+	}
+	span.SetAttributes(attribute.String("spec", spcKey))
+
 	defer func() {
 		if err != nil && IsSystemError(err) {
 			t.logger.Error().
@@ -122,19 +292,14 @@ func (t *Manager) RunInSandbox(
 				Field("spec", req.Language).
 				Msg("sandbox run failed")
 		}
+		metrics.Executions.WithLabelValues(spcKey, outcome(res, err)).Inc()
 	}()
 
-	// Try to get spec from specified language
-	spc, ok := t.spec.Spec().Get(req.Language)
-	if !ok {
-		return errUnsupportedLanguage
-	}
-
 	// Process the specified code if it is an inline expression
 	if req.InlineExpression {
 		// Check if the spec supports inline expressions
 		if !spc.SupportsTemplating() {
-			return errNoInlineExpressionsSupport
+			return nil, RejectedError{errNoInlineExpressionsSupport}
 		}
 
 		code := spc.Inline.Template
@@ -153,75 +318,386 @@ func (t *Manager) RunInSandbox(
 		fmt.Println(code)
 	}
 
-	// Wrap in RunSpec
-	runSpc := RunSpec{Spec: spc}
+	// Validate the passed files so that they can
+	// not be written outside of the host directory
+	// and the entry point file is present.
+	if err = validateFiles(req, spc.FileName); err != nil {
+		return nil, RejectedError{err}
+	}
 
-	// Get namespace as subdir
-	if runSpc.Subdir, err = t.ns.Get(); err != nil {
-		return SystemError{err}
+	// Validate the passed artifact patterns
+	if err = validateArtifacts(req); err != nil {
+		return nil, RejectedError{err}
 	}
 
-	// Set HostDir, Arguments and Environment Variables
-	runSpc.HostDir = t.cfg.Config().HostRootDir
-	runSpc.Arguments = req.Arguments
-	runSpc.Environment = req.Environment
+	// Resolve the resource limits of the sandbox
+	// from the request and the config.
+	lim, err := t.resolveLimits(req, spc)
+	if err != nil {
+		if !IsSystemError(err) {
+			err = RejectedError{err}
+		}
+		return nil, err
+	}
 
-	// If command is not specified, set file name as
-	// command.
-	if runSpc.Cmd == "" {
-		runSpc.Cmd = spc.FileName
+	// Interactive executions are not deterministic
+	// and therefore never cached.
+	if !t.cache.enabled() || !spc.Cacheable || req.Interactive {
+		return t.run(ctx, req, spcKey, spc, lim, chans)
 	}
 
-	// Create host directory + sub-directory on the
-	// Docker host.
+	key, err := t.cacheKey(ctx, spc, req, lim)
+	if err != nil {
+		return nil, SystemError{err}
+	}
+	if e, ok := t.cache.get(key); ok {
+		t.logger.Debug().Fields("key", key, "spec", req.Language).Msg("serving cached result")
+		span.SetAttributes(attribute.Bool("cached", true))
+		return t.replayCached(e, chans), nil
+	}
+
+	bufferCap, err := util.ParseMemoryStr(t.cfg.Config().Sandbox.StreamBufferCap)
+	if err != nil {
+		return nil, SystemError{err}
+	}
+	tee, teeChans := NewOutputTee(chans, int(bufferCap))
+	res, err = t.run(ctx, req, spcKey, spc, lim, teeChans)
+	stdout, stderr := tee.Close()
+	if err == nil && res.cacheable() {
+		t.cache.put(key, spc.Image, *res, stdout, stderr)
+	}
+
+	return res, err
+}
+
+// run executes the given request in a sandbox
+// of the resolved spec.
+func (t *Manager) run(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	spcKey string,
+	spc models.Spec,
+	lim ResourceLimits,
+	chans RunChannels,
+) (res *RunResult, err error) {
+	// Wait for a free slot if the number of concurrently
+	// running sandboxes is limited.
+	queueCtx, queueSpan := tracer.Start(ctx, "sandbox.queue")
+	release, err := t.queue.acquire(queueCtx, ClientFromContext(ctx), spcKey, spc.MaxConcurrent, chans.Queued)
+	tracing.End(queueSpan, err)
+	if err != nil {
+		if IsUnavailableError(err) {
+			err = RejectedError{err}
+		}
+		return nil, err
+	}
+	defer release()
+
+	// Try to claim a pre-created sandbox from the pool.
+	// Otherwise, a new RunSpec is assembled and the host
+	// directory is created.
+	runSpc, sbx, ok := t.pool.claim(spcKey, spc, req)
+	if !ok {
+		if runSpc, err = t.newRunSpec(spc, lim); err != nil {
+			return nil, err
+		}
+		if spc.Build == nil {
+			runSpc.Arguments = req.Arguments
+		}
+		runSpc.Environment = req.Environment
+
+		// Create host directory + sub-directory on the
+		// Docker host.
+		if err = t.file.CreateDirectory(runSpc.GetAssembledHostDir()); err != nil {
+			return nil, SystemError{err}
+		}
+	}
 	hostDir := runSpc.GetAssembledHostDir()
-	if err = t.file.CreateDirectory(hostDir); err != nil {
-		return SystemError{err}
+
+	// abort cleans up the host directory and the sandbox
+	// claimed from the pool, if any, because it is not
+	// tracked as running sandbox yet.
+	abort := func(err error) error {
+		var cErr error
+		if sbx != nil {
+			w := &sandboxWrapper{Sandbox: sbx, spec: spcKey, hostDir: hostDir}
+			cErr = t.killAndCleanUp(context.WithoutCancel(ctx), w)
+		} else {
+			cErr = t.file.DeleteDirectory(hostDir)
+		}
+		return SystemError{errors.Join(err, cErr)}
 	}
 
 	// Create code snippet file in the host + sub-directory
-	fileDir := path.Join(hostDir, spc.FileName)
-	if err = t.file.CreateFileWithContent(fileDir, req.Code); err != nil {
-		return SystemError{err}
+	if req.Code != "" {
+		fileDir := path.Join(hostDir, spc.FileName)
+		if err = t.file.CreateFileWithContent(fileDir, req.Code); err != nil {
+			return nil, abort(err)
+		}
+	}
+
+	// Create additional files in the host + sub-directory
+	for name, content := range req.Files {
+		fileDir := path.Join(hostDir, name)
+		if err = t.file.CreateDirectory(path.Dir(fileDir)); err != nil {
+			return nil, abort(err)
+		}
+		if err = t.file.CreateFileWithContent(fileDir, content); err != nil {
+			return nil, abort(err)
+		}
+	}
+
+	// Hand over the ownership of the host directory
+	// to the user the sandbox is run as.
+	if runSpc.UID != 0 || runSpc.GID != 0 {
+		if err = t.file.ChownDirectory(hostDir, runSpc.UID, runSpc.GID); err != nil {
+			return nil, abort(err)
+		}
+	}
+
+	// Create sandbox using RunSpec if none has been
+	// claimed from the pool
+	if sbx == nil {
+		if sbx, err = t.createSandbox(ctx, spcKey, runSpc); err != nil {
+			return nil, abort(err)
+		}
+		t.logger.Info().Fields("id", sbx.ID(), "spec", req.Language, "client", ClientFromContext(ctx)).Msg("created sandbox")
+	} else {
+		t.logger.Info().Fields("id", sbx.ID(), "spec", req.Language, "client", ClientFromContext(ctx)).Msg("claimed sandbox from pool")
 	}
 
-	// Create sandbox using RunSpec
-	sbx, err := t.sandbox.CreateSandbox(ctx, runSpc)
+	// The ID of the first sandbox identifies the
+	// execution across all of its phases.
+	runId := sbx.ID()
+	if chans.Spawn != nil {
+		chans.Spawn <- runId
+	}
+
+	if spc.Build == nil {
+		w := &sandboxWrapper{Sandbox: sbx, runId: runId, spec: spcKey, hostDir: hostDir}
+		return t.runSandbox(ctx, req, w, lim.Timeout, chans.Stdout, chans.Stderr, chans.Stats)
+	}
+
+	// Run the build phase. The host directory is kept
+	// after the build sandbox has been cleaned up so
+	// that the build output can be used by the run phase.
+	chans.sendPhase(PhaseEvent{Phase: PhaseBuildStart, RunId: runId})
+	build, err := t.runBuild(ctx, &sandboxWrapper{Sandbox: sbx, runId: runId, spec: spcKey}, lim.BuildTimeout)
 	if err != nil {
-		return SystemError{err}
+		t.file.DeleteDirectory(hostDir)
+		return nil, err
 	}
-	if cSpn != nil {
-		cSpn <- sbx.ID()
+	chans.sendPhase(PhaseEvent{Phase: PhaseBuildEnd, RunId: runId, Build: build})
+
+	if !build.Succeeded() {
+		t.logger.Debug().Fields("id", runId, "spec", req.Language).Msg("build failed")
+		if err = t.file.DeleteDirectory(hostDir); err != nil {
+			return nil, SystemError{err}
+		}
+		res = new(RunResult)
+		*res = build.RunResult
+		res.BuildFailed = !build.TimedOut && !build.OOMKilled && !build.Killed
+		res.Build = build
+		return res, nil
 	}
-	t.logger.Info().Fields("id", sbx.ID(), "spec", req.Language).Msg("created sandbox")
 
-	// Store sandbox to track run state later
-	wrapper := &sandboxWrapper{sbx, hostDir}
-	t.runningSandboxes.Store(sbx.ID(), wrapper)
+	// Create the sandbox of the run phase using the
+	// run command and the passed arguments.
+	runSpc.Cmd = runCmd(spc)
+	runSpc.Arguments = req.Arguments
+	if sbx, err = t.createSandbox(ctx, spcKey, runSpc); err != nil {
+		t.file.DeleteDirectory(hostDir)
+		return nil, SystemError{err}
+	}
+	t.logger.Info().Fields("id", sbx.ID(), "runid", runId, "spec", req.Language).Msg("created run sandbox")
 
-	timeout := time.Duration(t.cfg.Config().Sandbox.TimeoutSeconds) * time.Second
-	runCtx, cancelRunCtx := context.WithTimeoutCause(ctx, timeout, errTimedOut)
-	defer cancelRunCtx()
+	chans.sendPhase(PhaseEvent{Phase: PhaseRunStart, RunId: runId})
+	w := &sandboxWrapper{Sandbox: sbx, runId: runId, spec: spcKey, hostDir: hostDir}
+	if res, err = t.runSandbox(ctx, req, w, lim.Timeout, chans.Stdout, chans.Stderr, chans.Stats); err != nil {
+		return nil, err
+	}
+	res.Build = build
+
+	return res, nil
+}
+
+// runSandbox tracks the wrapped sandbox by its run ID,
+// passes the stdin of req to it and runs it until it
+// finishes or the timeout is reached. Afterwards, the
+// sandbox is cleaned up.
+func (t *Manager) runSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	w *sandboxWrapper,
+	timeout time.Duration,
+	cOut chan []byte,
+	cErr chan []byte,
+	cStats chan models.ResourceUsage,
+) (res *RunResult, err error) {
+	// Store sandbox to track run state later
+	w.client = ClientFromContext(ctx)
+	w.started = time.Now()
+	t.runningSandboxes.Store(w.runId, w)
 
-	err = sbx.Run(runCtx, cOut, cErr)
 	defer func() {
 		// Kill container if it is still running, delete the
 		// container after as well as delete the snippet host
-		// directory.
-		if cErr := t.killAndCleanUp(ctx, wrapper); cErr != nil {
+		// directory. This is done even if ctx has been canceled.
+		if cErr := t.killAndCleanUp(context.WithoutCancel(ctx), w); cErr != nil {
 			err = SystemError{error: errors.Join(err, cErr)}
 		}
-		t.logger.Info().Fields("id", sbx.ID(), "spec", req.Language).Msg("sandbox cleaned up")
+		t.logger.Info().Fields("id", w.ID(), "spec", req.Language).Msg("sandbox cleaned up")
 	}()
+
+	// Pass stdin to the sandbox and close it afterwards
+	// if the execution is not interactive
+	if req.Stdin != "" {
+		if err = w.WriteStdin([]byte(req.Stdin)); err != nil {
+			return nil, SystemError{err}
+		}
+	}
+	if !req.Interactive {
+		if err = w.CloseStdin(); err != nil {
+			return nil, SystemError{err}
+		}
+	}
+
+	runCtx, cancelRunCtx := context.WithTimeoutCause(ctx, timeout, errTimedOut)
+	defer cancelRunCtx()
+
+	runCtx, span := tracer.Start(runCtx, "sandbox.run",
+		trace.WithAttributes(attribute.String("id", w.ID())))
+	runTime := util.MeasureTime(func() {
+		res, err = w.Run(runCtx, cOut, cErr, cStats)
+	})
+	if errors.Is(err, errTimedOut) {
+		span.SetAttributes(attribute.Bool("timedout", true))
+	}
+	tracing.End(span, err)
+	metrics.SandboxRunDuration.WithLabelValues(w.spec).Observe(runTime.Seconds())
 	if err != nil {
-		if errors.Is(err, errTimedOut) {
-			t.logger.Debug().Fields("id", sbx.ID(), "spec", req.Language).Msg("execution timed out")
-			return err
+		switch {
+		case res != nil && errors.Is(err, errTimedOut):
+			t.logger.Debug().Fields("id", w.ID(), "spec", req.Language).Msg("execution timed out")
+			res.TimedOut = true
+		case res != nil && ctx.Err() != nil:
+			// The execution has been canceled after the spawn,
+			// so it is reported as killed with its usage.
+			t.logger.Debug().Fields("id", w.ID(), "spec", req.Language).Msg("execution canceled")
+			res.Killed = true
+		default:
+			return nil, SystemError{err}
+		}
+		err = nil
+	}
+
+	// Collect the requested artifacts before the
+	// host directory is deleted on clean up.
+	if w.hostDir != "" && len(req.Artifacts) != 0 {
+		res.Artifacts, res.ArtifactsTruncated, err = t.collectArtifacts(w.hostDir, req.Artifacts)
+		if err != nil {
+			return nil, SystemError{err}
 		}
-		return SystemError{err}
 	}
 
-	return err
+	return res, nil
+}
+
+// runBuild runs the wrapped build sandbox without
+// stdin and collects its output.
+func (t *Manager) runBuild(
+	ctx context.Context,
+	w *sandboxWrapper,
+	timeout time.Duration,
+) (build *BuildResult, err error) {
+	ctx, span := tracer.Start(ctx, "sandbox.build")
+	defer func() { tracing.End(span, err) }()
+
+	bufferCap, err := util.ParseMemoryStr(t.cfg.Config().Sandbox.StreamBufferCap)
+	if err != nil {
+		return nil, SystemError{err}
+	}
+
+	output := NewOutputCollector(int(bufferCap))
+
+	var res *RunResult
+	execTime := util.MeasureTime(func() {
+		res, err = t.runSandbox(ctx, &models.ExecutionRequest{}, w, timeout, output.Stdout, output.Stderr, nil)
+	})
+	stdOut, stdErr := output.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	build = &BuildResult{
+		RunResult: *res,
+		StdOut:    stdOut,
+		StdErr:    stdErr,
+		ExecTime:  execTime,
+	}
+	return build, nil
+}
+
+// createSandbox creates a sandbox of the spec with
+// the given key and observes the creation time.
+func (t *Manager) createSandbox(ctx context.Context, spcKey string, runSpc RunSpec) (sbx Sandbox, err error) {
+	ctx, span := tracer.Start(ctx, "sandbox.create")
+	defer func() { tracing.End(span, err) }()
+
+	createTime := util.MeasureTime(func() {
+		sbx, err = t.sandbox.CreateSandbox(ctx, runSpc)
+	})
+	metrics.SandboxCreateDuration.WithLabelValues(spcKey).Observe(createTime.Seconds())
+	return sbx, err
+}
+
+// newRunSpec wraps the given spec in a new RunSpec
+// with a unique sub directory, the given resource
+// limits and the configured sandbox user.
+func (t *Manager) newRunSpec(spc models.Spec, lim ResourceLimits) (runSpc RunSpec, err error) {
+	runSpc = RunSpec{Spec: spc}
+
+	// Get namespace as subdir
+	if runSpc.Subdir, err = t.ns.Get(); err != nil {
+		return runSpc, SystemError{err}
+	}
+
+	// Set HostDir and resource limits
+	runSpc.HostDir = t.cfg.Config().HostRootDir
+	runSpc.Limits = lim
+
+	// Set the user the sandbox is run as unless
+	// the spec opts out to use the image's user.
+	if spc.Hardening == nil || !spc.Hardening.RunAsImageUser {
+		runSpc.UID = t.cfg.Config().Sandbox.Hardening.UID
+		runSpc.GID = t.cfg.Config().Sandbox.Hardening.GID
+	}
+
+	// If the spec defines a build phase, the sandbox
+	// is created with the build command. Otherwise, the
+	// run command is used.
+	if spc.Build != nil {
+		runSpc.Cmd = spc.Build.Cmd
+	} else {
+		runSpc.Cmd = runCmd(spc)
+	}
+
+	return runSpc, nil
+}
+
+// runCmd returns the command of the run phase of
+// the given spec. If no run command is specified,
+// the spec's command is used or, if this is not set
+// as well, the file name.
+func runCmd(spc models.Spec) string {
+	if spc.Run != nil && spc.Run.Cmd != "" {
+		return spc.Run.Cmd
+	}
+	if spc.Cmd != "" {
+		return spc.Cmd
+	}
+	return spc.FileName
 }
 
 // KillAndCleanUp takes a sandbox ID and, if
@@ -244,10 +720,43 @@ func (t *Manager) KillAndCleanUp(ctx context.Context, id string) (ok bool, err e
 	return true, nil
 }
 
-// Cleanup tries to kill and delete all running sandboxes.
+// WriteStdin takes a sandbox ID and, if existing,
+// writes p to the stdin stream of the running sandbox.
+func (t *Manager) WriteStdin(id string, p []byte) (ok bool, err error) {
+	v, ok := t.runningSandboxes.Load(id)
+	if !ok {
+		return false, nil
+	}
+
+	if err = v.(Sandbox).WriteStdin(p); err != nil && !errors.Is(err, ErrStdinClosed) {
+		err = SystemError{err}
+	}
+
+	return true, err
+}
+
+// CloseStdin takes a sandbox ID and, if existing,
+// closes the stdin stream of the running sandbox.
+func (t *Manager) CloseStdin(id string) (ok bool, err error) {
+	v, ok := t.runningSandboxes.Load(id)
+	if !ok {
+		return false, nil
+	}
+
+	if err = v.(Sandbox).CloseStdin(); err != nil && !errors.Is(err, ErrStdinClosed) {
+		err = SystemError{err}
+	}
+
+	return true, err
+}
+
+// Cleanup tries to kill and delete all running and
+// pooled sandboxes.
 func (t *Manager) Cleanup(ctx context.Context) (errs []error) {
 	errs = []error{}
 
+	t.pool.close(ctx)
+
 	t.runningSandboxes.Range(func(key, value any) bool {
 		w, ok := value.(*sandboxWrapper)
 		if ok {
@@ -262,13 +771,59 @@ func (t *Manager) Cleanup(ctx context.Context) (errs []error) {
 	return errs
 }
 
+// RunningSandboxes returns the executions with a
+// running sandbox ordered by their start time.
+func (t *Manager) RunningSandboxes() []models.RunningSandbox {
+	sandboxes := []models.RunningSandbox{}
+	t.runningSandboxes.Range(func(_, value any) bool {
+		if w, ok := value.(*sandboxWrapper); ok {
+			sandboxes = append(sandboxes, models.RunningSandbox{
+				RunId:     w.runId,
+				SandboxId: w.ID(),
+				Language:  w.spec,
+				Client:    w.client,
+				Started:   w.started,
+			})
+		}
+		return true
+	})
+
+	slices.SortFunc(sandboxes, func(a, b models.RunningSandbox) int {
+		return a.Started.Compare(b.Started)
+	})
+
+	return sandboxes
+}
+
+// RunningCount returns the number of
+// executions with a running sandbox.
+func (t *Manager) RunningCount() (n int) {
+	t.runningSandboxes.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// QueueLength returns the number of executions
+// waiting for a free slot.
+func (t *Manager) QueueLength() int {
+	return t.queue.waitingCount()
+}
+
 // GetProvider returns the utilized sandbox provider instance.
 func (t *Manager) GetProvider() Provider {
 	return t.sandbox
 }
 
 func (t *Manager) killAndCleanUp(ctx context.Context, w *sandboxWrapper) (err error) {
+	ctx, span := tracer.Start(ctx, "sandbox.cleanup",
+		trace.WithAttributes(attribute.String("id", w.ID())))
+
+	start := time.Now()
 	defer func() {
+		tracing.End(span, err)
+		metrics.SandboxCleanupDuration.WithLabelValues(w.spec).Observe(time.Since(start).Seconds())
 		if err != nil {
 			t.logger.Error().
 				Err(err).
@@ -289,9 +844,54 @@ func (t *Manager) killAndCleanUp(ctx context.Context, w *sandboxWrapper) (err er
 	if err = w.Delete(ctx); err != nil {
 		return err
 	}
-	if err = t.file.DeleteDirectory(w.hostDir); err != nil {
-		return err
+	if w.hostDir != "" {
+		if err = t.file.DeleteDirectory(w.hostDir); err != nil {
+			return err
+		}
+	}
+	t.runningSandboxes.Delete(w.runId)
+	return nil
+}
+
+// outcome returns the outcome of an execution
+// with the given result and error for metrics.
+func outcome(res *RunResult, err error) string {
+	switch {
+	case err == nil:
+		return string(res.TerminationReason())
+	case IsSystemError(err):
+		return metrics.OutcomeError
+	case IsUnavailableError(err):
+		return metrics.OutcomeUnavailable
+	case errors.Is(err, context.Canceled):
+		return metrics.OutcomeCanceled
+	default:
+		return metrics.OutcomeInvalid
+	}
+}
+
+// validateFiles checks that all file paths passed in
+// req are local to the working directory and name a
+// file, and that the entry point file is passed either
+// as code or as file.
+func validateFiles(req *models.ExecutionRequest, entryFile string) error {
+	hasEntryFile := false
+	for name := range req.Files {
+		if !filepath.IsLocal(name) || path.Clean(name) == "." ||
+			strings.HasSuffix(name, "/") || strings.HasSuffix(name, string(filepath.Separator)) {
+			return fmt.Errorf("%w: %s", errInvalidFilePath, name)
+		}
+		if path.Clean(name) == path.Clean(entryFile) {
+			hasEntryFile = true
+		}
 	}
-	t.runningSandboxes.Delete(w.ID())
+
+	if req.Code == "" && !hasEntryFile {
+		return errMissingEntryFile
+	}
+	if req.Code != "" && hasEntryFile {
+		return errConflictingEntryFile
+	}
+
 	return nil
 }
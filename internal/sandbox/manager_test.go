@@ -0,0 +1,199 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/metrics"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+func TestValidateFiles(t *testing.T) {
+	expect := func(req *models.ExecutionRequest, expErr error) {
+		err := validateFiles(req, "main.go")
+		if !errors.Is(err, expErr) {
+			t.Errorf("error was %v (expected: %v)", err, expErr)
+		}
+	}
+
+	expect(&models.ExecutionRequest{Code: "code"}, nil)
+	expect(&models.ExecutionRequest{Code: "code", Files: map[string]string{
+		"go.mod":         "module test",
+		"pkg/util.go":    "package pkg",
+		"./pkg/util2.go": "package pkg",
+	}}, nil)
+	expect(&models.ExecutionRequest{Files: map[string]string{
+		"./main.go": "package main",
+	}}, nil)
+
+	expect(&models.ExecutionRequest{}, errMissingEntryFile)
+	expect(&models.ExecutionRequest{Files: map[string]string{
+		"util.go": "package main",
+	}}, errMissingEntryFile)
+	expect(&models.ExecutionRequest{Code: "code", Files: map[string]string{
+		"main.go": "package main",
+	}}, errConflictingEntryFile)
+
+	expect(&models.ExecutionRequest{Code: "code", Files: map[string]string{
+		"../main.go": "",
+	}}, errInvalidFilePath)
+	expect(&models.ExecutionRequest{Code: "code", Files: map[string]string{
+		"pkg/../../main.go": "",
+	}}, errInvalidFilePath)
+	expect(&models.ExecutionRequest{Code: "code", Files: map[string]string{
+		"/etc/passwd": "",
+	}}, errInvalidFilePath)
+	expect(&models.ExecutionRequest{Code: "code", Files: map[string]string{
+		"": "",
+	}}, errInvalidFilePath)
+	for _, name := range []string{".", "./", "pkg/..", "pkg/"} {
+		expect(&models.ExecutionRequest{Code: "code", Files: map[string]string{
+			name: "",
+		}}, errInvalidFilePath)
+	}
+}
+
+func TestRunInSandboxCanceled(t *testing.T) {
+	mgr, _ := newTestManager(t, models.SpecMap{
+		"python": {Image: "python", FileName: "main.py"},
+	})
+
+	// The execution is canceled after the spawn and
+	// reported as killed together with its usage.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := mgr.RunInSandbox(ctx, &models.ExecutionRequest{Language: "python", Code: "code"}, RunChannels{
+		Stdout: make(chan []byte),
+		Stderr: make(chan []byte),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TerminationReason() != models.TerminationKilled || res.Usage == nil {
+		t.Errorf("invalid result of canceled execution: %+v", res)
+	}
+
+	_, err = mgr.RunInSandbox(context.Background(), &models.ExecutionRequest{Language: "cobol"}, RunChannels{})
+	if !IsRejectedError(err) {
+		t.Errorf("invalid error for unsupported language: %v", err)
+	}
+}
+
+func TestRunInSandboxBuildPhase(t *testing.T) {
+	mgr, prov := newTestManager(t, models.SpecMap{
+		"c": {
+			Image:    "gcc",
+			FileName: "main.c",
+			Build:    &models.PhaseSpec{Cmd: "cc main.c"},
+			Run:      &models.PhaseSpec{Cmd: "./a.out"},
+		},
+	})
+	prov.exitCodes = map[string]int{"./a.out": 3}
+
+	cPhase := make(chan PhaseEvent, 3)
+	chans := RunChannels{
+		Stdout: make(chan []byte),
+		Stderr: make(chan []byte),
+		Phase:  cPhase,
+	}
+
+	res, err := mgr.RunInSandbox(context.Background(), &models.ExecutionRequest{Language: "c", Code: "code"}, chans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ExitCode != 3 || res.TerminationReason() != models.TerminationExited {
+		t.Errorf("invalid run result: %+v", res)
+	}
+	if res.Build == nil || !res.Build.Succeeded() {
+		t.Errorf("invalid build result: %+v", res.Build)
+	}
+	if !slices.Equal(prov.cmds, []string{"cc main.c", "./a.out"}) {
+		t.Errorf("invalid commands: %v", prov.cmds)
+	}
+	close(cPhase)
+	var phases []Phase
+	for evt := range cPhase {
+		if evt.RunId != "sbx-1" {
+			t.Errorf("invalid run ID: %s", evt.RunId)
+		}
+		phases = append(phases, evt.Phase)
+	}
+	if !slices.Equal(phases, []Phase{PhaseBuildStart, PhaseBuildEnd, PhaseRunStart}) {
+		t.Errorf("invalid phases: %v", phases)
+	}
+
+	prov.exitCodes = map[string]int{"cc main.c": 1}
+	res, err = mgr.RunInSandbox(context.Background(), &models.ExecutionRequest{Language: "c", Code: "code"}, RunChannels{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TerminationReason() != models.TerminationBuildFailed || res.Build.ExitCode != 1 {
+		t.Errorf("invalid result of failed build: %+v", res)
+	}
+	if n := prov.created.Load(); n != 3 {
+		t.Errorf("created %d sandboxes (expected: 3)", n)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	cases := []struct {
+		res      *RunResult
+		err      error
+		expected string
+	}{
+		{&RunResult{}, nil, "exited"},
+		{&RunResult{TimedOut: true}, nil, "timed_out"},
+		{nil, SystemError{errors.New("docker")}, metrics.OutcomeError},
+		{nil, UnavailableError{ErrQueueFull}, metrics.OutcomeUnavailable},
+		{nil, context.Canceled, metrics.OutcomeCanceled},
+		{nil, errMissingEntryFile, metrics.OutcomeInvalid},
+	}
+	for _, c := range cases {
+		if o := outcome(c.res, c.err); o != c.expected {
+			t.Errorf("outcome(%v, %v) = %s (expected: %s)", c.res, c.err, o, c.expected)
+		}
+	}
+}
+
+func TestPrepareEnvironment(t *testing.T) {
+	mgr, _ := newTestManager(t, models.SpecMap{
+		"go":     {Image: "golang", FileName: "main.go", PoolSize: 2},
+		"golang": {Use: "go"},
+		"python": {Image: "python", FileName: "main.py", PoolSize: 1},
+	})
+	mgr.pool.fill(context.Background())
+
+	if err := mgr.PrepareEnvironment(context.Background(), "golang", true); err != nil {
+		t.Fatal(err)
+	}
+	if info := mgr.pool.info(); info["go"].Available != 0 || info["python"].Available != 1 {
+		t.Errorf("invalid pool info after preparing go: %+v", info)
+	}
+
+	if err := mgr.PrepareEnvironment(context.Background(), "rust", true); !errors.Is(err, errUnsupportedLanguage) {
+		t.Errorf("invalid error for unsupported language: %v", err)
+	}
+}
+
+func TestRunningSandboxes(t *testing.T) {
+	mgr, _ := newTestManager(t, models.SpecMap{})
+
+	now := time.Now()
+	mgr.runningSandboxes.Store("b", &sandboxWrapper{
+		Sandbox: &fakeSandbox{id: "sbx-b"}, runId: "b", spec: "go", client: "key:b", started: now,
+	})
+	mgr.runningSandboxes.Store("a", &sandboxWrapper{
+		Sandbox: &fakeSandbox{id: "sbx-a"}, runId: "a", spec: "python", client: "1.2.3.4", started: now.Add(-time.Second),
+	})
+
+	expected := []models.RunningSandbox{
+		{RunId: "a", SandboxId: "sbx-a", Language: "python", Client: "1.2.3.4", Started: now.Add(-time.Second)},
+		{RunId: "b", SandboxId: "sbx-b", Language: "go", Client: "key:b", Started: now},
+	}
+	if res := mgr.RunningSandboxes(); !slices.Equal(res, expected) {
+		t.Errorf("invalid running sandboxes: %+v", res)
+	}
+}
@@ -0,0 +1,121 @@
+package sandbox
+
+import (
+	"github.com/ranna-go/ranna/pkg/cappedbuffer"
+)
+
+// OutputCollector collects the output of an
+// execution passed into its channels into
+// capped buffers.
+type OutputCollector struct {
+	Stdout chan []byte
+	Stderr chan []byte
+
+	stdOut *cappedbuffer.CappedBuffer
+	stdErr *cappedbuffer.CappedBuffer
+	cClose chan struct{}
+	cDone  chan struct{}
+}
+
+// NewOutputCollector returns a new OutputCollector
+// whose buffers grow to at most bufferCap bytes each
+// and starts collecting the output.
+func NewOutputCollector(bufferCap int) *OutputCollector {
+	t := &OutputCollector{
+		Stdout: make(chan []byte),
+		Stderr: make(chan []byte),
+		stdOut: cappedbuffer.New([]byte{}, bufferCap),
+		stdErr: cappedbuffer.New([]byte{}, bufferCap),
+		cClose: make(chan struct{}),
+		cDone:  make(chan struct{}),
+	}
+
+	go func() {
+		defer close(t.cDone)
+		for {
+			select {
+			case <-t.cClose:
+				return
+			case p := <-t.Stdout:
+				t.stdOut.Write(p)
+			case p := <-t.Stderr:
+				t.stdErr.Write(p)
+			}
+		}
+	}()
+
+	return t
+}
+
+// Close stops collecting the output and returns
+// the collected stdout and stderr output.
+func (t *OutputCollector) Close() (stdout, stderr string) {
+	close(t.cClose)
+	<-t.cDone
+	return t.stdOut.String(), t.stdErr.String()
+}
+
+// OutputTee passes the output and the run ID of
+// an execution through to the Stdout, Stderr and
+// Spawn channels of a RunChannels instance and
+// collects them.
+type OutputTee struct {
+	collector *OutputCollector
+	runId     string
+	cStop     chan struct{}
+	cDone     chan struct{}
+}
+
+// NewOutputTee returns a new OutputTee collecting
+// at most bufferCap bytes of each stream and a copy
+// of chans whose Stdout, Stderr and Spawn channels
+// are passed through to chans.
+func NewOutputTee(chans RunChannels, bufferCap int) (*OutputTee, RunChannels) {
+	t := &OutputTee{
+		collector: NewOutputCollector(bufferCap),
+		cStop:     make(chan struct{}),
+		cDone:     make(chan struct{}),
+	}
+
+	teeChans := chans
+	teeChans.Stdout = make(chan []byte)
+	teeChans.Stderr = make(chan []byte)
+	teeChans.Spawn = make(chan string)
+
+	go func() {
+		defer close(t.cDone)
+		for {
+			select {
+			case <-t.cStop:
+				return
+			case p := <-teeChans.Stdout:
+				chans.Stdout <- p
+				t.collector.Stdout <- p
+			case p := <-teeChans.Stderr:
+				chans.Stderr <- p
+				t.collector.Stderr <- p
+			case runId := <-teeChans.Spawn:
+				t.runId = runId
+				if chans.Spawn != nil {
+					chans.Spawn <- runId
+				}
+			}
+		}
+	}()
+
+	return t, teeChans
+}
+
+// Close stops passing through the output and
+// returns the collected stdout and stderr output.
+func (t *OutputTee) Close() (stdout, stderr string) {
+	close(t.cStop)
+	<-t.cDone
+	return t.collector.Close()
+}
+
+// RunId returns the run ID of the execution.
+// It must only be called after Close.
+func (t *OutputTee) RunId() string {
+	return t.runId
+}
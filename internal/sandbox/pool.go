@@ -0,0 +1,279 @@
+package sandbox
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/log"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+const poolRefillInterval = 1 * time.Minute
+
+// pooledSandbox wraps a pre-created sandbox together
+// with the spec it has been created from and the
+// RunSpec used to create it.
+type pooledSandbox struct {
+	key     string
+	spec    models.Spec
+	runSpec RunSpec
+	sandbox Sandbox
+}
+
+// pool keeps a number of pre-created sandboxes per
+// spec, defined by the spec's pool size, which can be
+// claimed by executions to skip the creation of the
+// sandbox. Claimed sandboxes are refilled in the
+// background.
+type pool struct {
+	mgr    *Manager
+	logger rogu.Logger
+
+	mtx     sync.Mutex
+	entries map[string][]*pooledSandbox
+	sizes   map[string]int
+	closed  bool
+
+	fillMtx sync.Mutex
+	cRefill chan struct{}
+}
+
+func newPool(mgr *Manager) *pool {
+	return &pool{
+		mgr:     mgr,
+		logger:  log.Tagged("Pool"),
+		entries: make(map[string][]*pooledSandbox),
+		sizes:   make(map[string]int),
+		cRefill: make(chan struct{}, 1),
+	}
+}
+
+// start fills the pool and keeps refilling it in the
+// background until ctx is canceled.
+func (t *pool) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(poolRefillInterval)
+		defer ticker.Stop()
+
+		for {
+			t.fill(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-t.cRefill:
+			}
+		}
+	}()
+}
+
+// claim takes a pre-created sandbox for the spec
+// with the given key from the pool.
+//
+// Because the arguments, environment variables and
+// resource limits of a sandbox are set on creation,
+// only requests which do not specify them can be
+// served from the pool.
+func (t *pool) claim(key string, spc models.Spec, req *models.ExecutionRequest) (RunSpec, Sandbox, bool) {
+	if len(req.Arguments) != 0 || len(req.Environment) != 0 || req.Memory != "" || req.CPUs != 0 {
+		return RunSpec{}, nil, false
+	}
+
+	t.mtx.Lock()
+	entries := t.entries[key]
+	if len(entries) == 0 {
+		t.mtx.Unlock()
+		return RunSpec{}, nil, false
+	}
+	entry := entries[len(entries)-1]
+	t.entries[key] = entries[:len(entries)-1]
+	t.mtx.Unlock()
+
+	t.requestRefill()
+
+	// The spec might have been updated since the
+	// sandbox has been created.
+	if !reflect.DeepEqual(entry.spec, spc) {
+		t.discard(context.Background(), entry)
+		return RunSpec{}, nil, false
+	}
+
+	return entry.runSpec, entry.sandbox, true
+}
+
+// info returns the pool size and the number of
+// available sandboxes per spec.
+func (t *pool) info() map[string]models.PoolInfo {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	info := make(map[string]models.PoolInfo, len(t.sizes))
+	for key, size := range t.sizes {
+		info[key] = models.PoolInfo{
+			Size:      size,
+			Available: len(t.entries[key]),
+		}
+	}
+	return info
+}
+
+// close purges the pool and prevents it from
+// being refilled.
+func (t *pool) close(ctx context.Context) {
+	t.mtx.Lock()
+	t.closed = true
+	t.mtx.Unlock()
+
+	t.purge(ctx)
+}
+
+// purge removes and cleans up the pooled sandboxes
+// of the specs with the given keys or, if no keys are
+// passed, all pooled sandboxes. If the pool has been
+// started, it is refilled in the background afterwards.
+func (t *pool) purge(ctx context.Context, keys ...string) {
+	t.mtx.Lock()
+	var entries map[string][]*pooledSandbox
+	if len(keys) == 0 {
+		entries = t.entries
+		t.entries = make(map[string][]*pooledSandbox)
+	} else {
+		entries = make(map[string][]*pooledSandbox, len(keys))
+		for _, key := range keys {
+			entries[key] = t.entries[key]
+			delete(t.entries, key)
+		}
+	}
+	t.mtx.Unlock()
+
+	for _, es := range entries {
+		for _, e := range es {
+			t.discard(ctx, e)
+		}
+	}
+
+	t.requestRefill()
+}
+
+func (t *pool) requestRefill() {
+	select {
+	case t.cRefill <- struct{}{}:
+	default:
+	}
+}
+
+// fill creates sandboxes for all specs until the
+// specified pool sizes are reached. Sandboxes of specs
+// which have been removed or whose pool size has been
+// reduced are discarded.
+func (t *pool) fill(ctx context.Context) {
+	t.fillMtx.Lock()
+	defer t.fillMtx.Unlock()
+
+	sizes := make(map[string]int)
+	for key, spc := range t.mgr.spec.Spec().GetSnapshot() {
+		if spc.Use == "" && spc.Image != "" && spc.PoolSize > 0 {
+			sizes[key] = spc.PoolSize
+		}
+	}
+
+	var excess []*pooledSandbox
+	t.mtx.Lock()
+	t.sizes = sizes
+	for key, entries := range t.entries {
+		if len(entries) > sizes[key] {
+			excess = append(excess, entries[sizes[key]:]...)
+			t.entries[key] = entries[:sizes[key]]
+		}
+	}
+	t.mtx.Unlock()
+
+	for _, e := range excess {
+		t.discard(ctx, e)
+	}
+
+	for key, size := range sizes {
+		for {
+			t.mtx.Lock()
+			n, closed := len(t.entries[key]), t.closed
+			t.mtx.Unlock()
+			if n >= size || closed || ctx.Err() != nil {
+				break
+			}
+
+			entry, err := t.create(ctx, key)
+			if err != nil {
+				t.logger.Error().Err(err).Field("spec", key).Msg("failed creating pooled sandbox")
+				break
+			}
+
+			t.mtx.Lock()
+			closed = t.closed
+			if !closed {
+				t.entries[key] = append(t.entries[key], entry)
+			}
+			t.mtx.Unlock()
+
+			if closed {
+				t.discard(ctx, entry)
+			}
+		}
+	}
+}
+
+// create creates a new sandbox for the spec with the
+// given key using the default resource limits.
+func (t *pool) create(ctx context.Context, key string) (entry *pooledSandbox, err error) {
+	spc, ok := t.mgr.spec.Spec().Get(key)
+	if !ok {
+		return nil, errUnsupportedLanguage
+	}
+
+	lim, err := t.mgr.resolveLimits(&models.ExecutionRequest{}, spc)
+	if err != nil {
+		return nil, err
+	}
+
+	runSpc, err := t.mgr.newRunSpec(spc, lim)
+	if err != nil {
+		return nil, err
+	}
+
+	hostDir := runSpc.GetAssembledHostDir()
+	if err = t.mgr.file.CreateDirectory(hostDir); err != nil {
+		return nil, err
+	}
+
+	sbx, err := t.mgr.sandbox.CreateSandbox(ctx, runSpc)
+	if err != nil {
+		t.mgr.file.DeleteDirectory(hostDir)
+		return nil, err
+	}
+	t.logger.Debug().Fields("id", sbx.ID(), "spec", key).Msg("created pooled sandbox")
+
+	entry = &pooledSandbox{
+		key:     key,
+		spec:    spc,
+		runSpec: runSpc,
+		sandbox: sbx,
+	}
+	return entry, nil
+}
+
+// discard deletes the sandbox and the host directory
+// of the given pooled sandbox.
+func (t *pool) discard(ctx context.Context, entry *pooledSandbox) {
+	w := &sandboxWrapper{
+		Sandbox: entry.sandbox,
+		runId:   entry.sandbox.ID(),
+		spec:    entry.key,
+		hostDir: entry.runSpec.GetAssembledHostDir(),
+	}
+	if err := t.mgr.killAndCleanUp(ctx, w); err != nil {
+		t.logger.Error().Err(err).Field("id", w.ID()).Msg("failed discarding pooled sandbox")
+	}
+}
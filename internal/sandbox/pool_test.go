@@ -0,0 +1,168 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/file"
+	"github.com/ranna-go/ranna/internal/namespace"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type fakeSandbox struct {
+	id       string
+	exitCode int
+	deleted  atomic.Bool
+}
+
+func (t *fakeSandbox) ID() string { return t.id }
+func (t *fakeSandbox) Run(ctx context.Context, _ chan []byte, _ chan []byte, _ chan models.ResourceUsage) (*RunResult, error) {
+	// Like the Docker sandbox, the state is returned
+	// together with the cause if ctx has been canceled.
+	return &RunResult{ExitCode: t.exitCode, Usage: &models.ResourceUsage{CPUTimeMS: 10}}, context.Cause(ctx)
+}
+func (t *fakeSandbox) WriteStdin([]byte) error                 { return nil }
+func (t *fakeSandbox) CloseStdin() error                       { return nil }
+func (t *fakeSandbox) IsRunning(context.Context) (bool, error) { return false, nil }
+func (t *fakeSandbox) Kill(context.Context) error              { return nil }
+func (t *fakeSandbox) Delete(context.Context) error {
+	t.deleted.Store(true)
+	return nil
+}
+
+type fakeProvider struct {
+	created atomic.Int32
+
+	// exitCodes maps commands to the exit code
+	// their sandboxes return on run.
+	exitCodes map[string]int
+
+	// digests maps images to their digest.
+	digests map[string]string
+
+	mtx  sync.Mutex
+	cmds []string
+}
+
+func (t *fakeProvider) Prepare(context.Context, models.Spec, bool) error { return nil }
+func (t *fakeProvider) ImageDigest(_ context.Context, image string) (string, error) {
+	return t.digests[image], nil
+}
+func (t *fakeProvider) Info(context.Context) (*models.SandboxInfo, error) {
+	return &models.SandboxInfo{}, nil
+}
+func (t *fakeProvider) CreateSandbox(_ context.Context, spc RunSpec) (Sandbox, error) {
+	n := t.created.Add(1)
+	t.mtx.Lock()
+	t.cmds = append(t.cmds, spc.Cmd)
+	t.mtx.Unlock()
+	return &fakeSandbox{id: fmt.Sprintf("sbx-%d", n), exitCode: t.exitCodes[spc.Cmd]}, nil
+}
+
+type specMapProvider struct {
+	m *spec.SafeSpecMap
+}
+
+func (t specMapProvider) Spec() *spec.SafeSpecMap { return t.m }
+
+func newTestManager(t *testing.T, specs models.SpecMap) (*Manager, *fakeProvider) {
+	prov := &fakeProvider{}
+	mgr, err := NewManager(
+		prov,
+		specMapProvider{spec.NewSafeSpecMap(specs)},
+		file.NewDummyFileProvider(),
+		staticConfig{&config.Config{Sandbox: config.Sandbox{
+			TimeoutSeconds:  5,
+			StreamBufferCap: "1M",
+		}}},
+		namespace.NewDummyProvider("test"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mgr, prov
+}
+
+func TestPool(t *testing.T) {
+	mgr, prov := newTestManager(t, models.SpecMap{
+		"go":     {Image: "golang", FileName: "main.go", PoolSize: 2},
+		"golang": {Use: "go"},
+		"python": {Image: "python", FileName: "main.py"},
+	})
+	p := mgr.pool
+
+	p.fill(context.Background())
+	if n := prov.created.Load(); n != 2 {
+		t.Fatalf("created %d sandboxes (expected: 2)", n)
+	}
+	if info := p.info(); info["go"].Available != 2 || info["go"].Size != 2 {
+		t.Errorf("invalid pool info: %+v", info)
+	}
+
+	key, spc, _ := mgr.spec.Spec().Resolve("golang")
+
+	_, _, ok := p.claim(key, spc, &models.ExecutionRequest{Arguments: []string{"arg"}})
+	if ok {
+		t.Error("claimed sandbox for request with arguments")
+	}
+
+	_, sbx, ok := p.claim(key, spc, &models.ExecutionRequest{})
+	if !ok || sbx == nil {
+		t.Fatal("could not claim sandbox")
+	}
+	if info := p.info(); info["go"].Available != 1 {
+		t.Errorf("invalid pool info after claim: %+v", info)
+	}
+
+	p.fill(context.Background())
+	if n := prov.created.Load(); n != 3 {
+		t.Errorf("created %d sandboxes after refill (expected: 3)", n)
+	}
+
+	spc.Image = "golang:new"
+	if _, _, ok = p.claim(key, spc, &models.ExecutionRequest{}); ok {
+		t.Error("claimed sandbox of outdated spec")
+	}
+
+	p.close(context.Background())
+	p.fill(context.Background())
+	if info := p.info(); info["go"].Available != 0 {
+		t.Errorf("pool has been refilled after close: %+v", info)
+	}
+}
+
+// failingFileProvider fails writing files.
+type failingFileProvider struct {
+	*file.DummyFileProvider
+}
+
+func (t failingFileProvider) CreateFileWithContent(string, string) error {
+	return errors.New("disk full")
+}
+
+func TestPoolClaimedSandboxCleanedUpOnFailure(t *testing.T) {
+	mgr, _ := newTestManager(t, models.SpecMap{
+		"go": {Image: "golang", FileName: "main.go", PoolSize: 1},
+	})
+	mgr.file = failingFileProvider{file.NewDummyFileProvider()}
+
+	mgr.pool.fill(context.Background())
+	sbx := mgr.pool.entries["go"][0].sandbox.(*fakeSandbox)
+
+	_, err := mgr.RunInSandbox(context.Background(), &models.ExecutionRequest{Language: "go", Code: "code"}, RunChannels{})
+	if !IsSystemError(err) {
+		t.Fatalf("expected system error, got %v", err)
+	}
+	if !sbx.deleted.Load() {
+		t.Error("claimed sandbox has not been deleted")
+	}
+	if info := mgr.pool.info(); info["go"].Available != 0 {
+		t.Errorf("invalid pool info: %+v", info)
+	}
+}
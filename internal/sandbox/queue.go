@@ -0,0 +1,262 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull is returned when an execution can
+	// not be queued because the queue is full.
+	ErrQueueFull = errors.New("execution queue is full")
+	// ErrQueueTimeout is returned when an execution
+	// has been waiting in the queue for too long.
+	ErrQueueTimeout = errors.New("timed out waiting in execution queue")
+)
+
+// UnavailableError wraps an error returned when an
+// execution can currently not be served because the
+// server is at its capacity.
+type UnavailableError struct {
+	error
+}
+
+func (t UnavailableError) Unwrap() error {
+	return t.error
+}
+
+// IsUnavailableError returns true when the passed
+// error is type of UnavailableError.
+func IsUnavailableError(err error) bool {
+	var unavailableError UnavailableError
+	return errors.As(err, &unavailableError)
+}
+
+// queueTicket represents an execution waiting
+// for or holding a slot of the queue.
+type queueTicket struct {
+	client  string
+	spec    string
+	maxSpec int
+
+	position  int
+	granted   bool
+	cReady    chan struct{}
+	cPosition chan int
+}
+
+// queue limits the number of concurrently running
+// sandboxes globally and per spec.
+//
+// Waiting executions are dispatched fairly between
+// clients in a round-robin manner and in FIFO order
+// for each client.
+type queue struct {
+	maxConcurrent int
+	maxLength     int
+	timeout       time.Duration
+
+	mtx           sync.Mutex
+	running       int
+	runningBySpec map[string]int
+	waiting       map[string][]*queueTicket
+	clients       []string
+	length        int
+}
+
+func newQueue(maxConcurrent, maxLength int, timeout time.Duration) *queue {
+	return &queue{
+		maxConcurrent: maxConcurrent,
+		maxLength:     maxLength,
+		timeout:       timeout,
+		runningBySpec: make(map[string]int),
+		waiting:       make(map[string][]*queueTicket),
+	}
+}
+
+// acquire blocks until a slot is available for an
+// execution of the given client and spec. maxSpec is
+// the maximum number of concurrent executions of the
+// spec, where 0 means no limit.
+//
+// While waiting, the position in the queue is passed
+// into cPosition on every change, if not nil.
+//
+// The returned release function must be called when
+// the execution has finished.
+func (t *queue) acquire(
+	ctx context.Context,
+	client, spec string,
+	maxSpec int,
+	cPosition chan int,
+) (release func(), err error) {
+	tk := &queueTicket{
+		client:    client,
+		spec:      spec,
+		maxSpec:   maxSpec,
+		cReady:    make(chan struct{}),
+		cPosition: make(chan int, 1),
+	}
+	release = func() { t.release(tk) }
+
+	t.mtx.Lock()
+	t.enqueue(tk)
+	t.dispatch()
+	if !tk.granted && t.maxLength > 0 && t.length > t.maxLength {
+		t.remove(tk)
+		t.mtx.Unlock()
+		return nil, UnavailableError{ErrQueueFull}
+	}
+	t.updatePositions()
+	t.mtx.Unlock()
+
+	var cTimeout <-chan time.Time
+	if t.timeout > 0 {
+		timer := time.NewTimer(t.timeout)
+		defer timer.Stop()
+		cTimeout = timer.C
+	}
+
+	for {
+		select {
+		case <-tk.cReady:
+			return release, nil
+		case pos := <-tk.cPosition:
+			if cPosition != nil {
+				select {
+				case cPosition <- pos:
+				case <-ctx.Done():
+				}
+			}
+		case <-ctx.Done():
+			if t.cancel(tk) {
+				return release, nil
+			}
+			return nil, ctx.Err()
+		case <-cTimeout:
+			if t.cancel(tk) {
+				return release, nil
+			}
+			return nil, UnavailableError{ErrQueueTimeout}
+		}
+	}
+}
+
+// waitingCount returns the number of tickets
+// waiting for a free slot.
+func (t *queue) waitingCount() int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.length
+}
+
+// enqueue appends tk to the queue of its client.
+func (t *queue) enqueue(tk *queueTicket) {
+	if len(t.waiting[tk.client]) == 0 {
+		t.clients = append(t.clients, tk.client)
+	}
+	t.waiting[tk.client] = append(t.waiting[tk.client], tk)
+	t.length++
+}
+
+// remove removes the waiting tk from the queue.
+func (t *queue) remove(tk *queueTicket) {
+	tks := t.waiting[tk.client]
+	i := slices.Index(tks, tk)
+	if i < 0 {
+		return
+	}
+	t.length--
+	if tks = slices.Delete(tks, i, i+1); len(tks) != 0 {
+		t.waiting[tk.client] = tks
+		return
+	}
+	delete(t.waiting, tk.client)
+	t.clients = slices.DeleteFunc(t.clients, func(c string) bool { return c == tk.client })
+}
+
+// cancel removes tk from the queue. If tk has been
+// granted a slot in the meantime, true is returned
+// and the slot must be released.
+func (t *queue) cancel(tk *queueTicket) (granted bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if tk.granted {
+		return true
+	}
+	t.remove(tk)
+	t.updatePositions()
+	return false
+}
+
+func (t *queue) release(tk *queueTicket) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.running--
+	t.runningBySpec[tk.spec]--
+	if t.runningBySpec[tk.spec] <= 0 {
+		delete(t.runningBySpec, tk.spec)
+	}
+	t.dispatch()
+	t.updatePositions()
+}
+
+// order returns the waiting tickets in the order
+// they are dispatched when no spec limit applies.
+func (t *queue) order() []*queueTicket {
+	order := make([]*queueTicket, 0, t.length)
+	for i := 0; len(order) < t.length; i++ {
+		for _, c := range t.clients {
+			if tks := t.waiting[c]; i < len(tks) {
+				order = append(order, tks[i])
+			}
+		}
+	}
+	return order
+}
+
+// dispatch grants slots to waiting tickets as long
+// as slots are available.
+func (t *queue) dispatch() {
+	for _, tk := range t.order() {
+		if t.maxConcurrent > 0 && t.running >= t.maxConcurrent {
+			return
+		}
+		if tk.maxSpec > 0 && t.runningBySpec[tk.spec] >= tk.maxSpec {
+			continue
+		}
+
+		t.remove(tk)
+		t.running++
+		t.runningBySpec[tk.spec]++
+		tk.granted = true
+		close(tk.cReady)
+
+		// The client is moved to the end of the round
+		// so that other clients are served first.
+		if i := slices.Index(t.clients, tk.client); i >= 0 {
+			t.clients = append(slices.Delete(t.clients, i, i+1), tk.client)
+		}
+	}
+}
+
+// updatePositions passes the current position to
+// all waiting tickets whose position has changed.
+func (t *queue) updatePositions() {
+	for i, tk := range t.order() {
+		if tk.position == i+1 {
+			continue
+		}
+		tk.position = i + 1
+		select {
+		case <-tk.cPosition:
+		default:
+		}
+		tk.cPosition <- tk.position
+	}
+}
@@ -0,0 +1,92 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	q := newQueue(1, 3, 0)
+	ctx := context.Background()
+
+	releaseA, err := q.acquire(ctx, "x", "go", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		name    string
+		release func()
+	}
+	cGranted := make(chan result, 3)
+	enqueue := func(name, client string) chan int {
+		cPos := make(chan int, 10)
+		go func() {
+			release, err := q.acquire(ctx, client, "go", 0, cPos)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			cGranted <- result{name, release}
+		}()
+		return cPos
+	}
+	waitPosition := func(cPos chan int, exp int) {
+		for {
+			select {
+			case pos := <-cPos:
+				if pos == exp {
+					return
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("position %d not reached", exp)
+			}
+		}
+	}
+
+	cPosB := enqueue("B", "x")
+	waitPosition(cPosB, 1)
+	cPosC := enqueue("C", "x")
+	waitPosition(cPosC, 2)
+	cPosD := enqueue("D", "y")
+	waitPosition(cPosD, 2)
+	waitPosition(cPosC, 3)
+
+	if _, err = q.acquire(ctx, "z", "go", 0, nil); !errors.Is(err, ErrQueueFull) || !IsUnavailableError(err) {
+		t.Errorf("error was %v (expected: %v)", err, ErrQueueFull)
+	}
+
+	// Client y must be served before the second
+	// execution of client x.
+	releaseA()
+	for _, exp := range []string{"B", "D", "C"} {
+		res := <-cGranted
+		if res.name != exp {
+			t.Fatalf("granted %s (expected: %s)", res.name, exp)
+		}
+		res.release()
+	}
+}
+
+func TestQueueSpecLimit(t *testing.T) {
+	q := newQueue(0, 0, 50*time.Millisecond)
+	ctx := context.Background()
+
+	release, err := q.acquire(ctx, "x", "go", 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = q.acquire(ctx, "y", "python", 1, nil); err != nil {
+		t.Errorf("execution of other spec has been queued: %v", err)
+	}
+	if _, err = q.acquire(ctx, "y", "go", 1, nil); !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("error was %v (expected: %v)", err, ErrQueueTimeout)
+	}
+
+	release()
+	if _, err = q.acquire(ctx, "y", "go", 1, nil); err != nil {
+		t.Error(err)
+	}
+}
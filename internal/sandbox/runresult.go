@@ -0,0 +1,109 @@
+package sandbox
+
+import (
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// RunResult wraps the final state of a
+// sandbox execution.
+//
+// If the spec defines a build phase, Build
+// contains the result of the build phase.
+//
+// Artifacts contains the files requested as
+// artifacts which have been collected after
+// the execution.
+//
+// Usage contains the resources used by the
+// execution, if available.
+//
+// Cached is set if the result has been served
+// from the result cache.
+type RunResult struct {
+	ExitCode    int
+	OOMKilled   bool
+	Killed      bool
+	TimedOut    bool
+	BuildFailed bool
+
+	Build *BuildResult
+
+	Artifacts          []models.Artifact
+	ArtifactsTruncated bool
+
+	Usage *models.ResourceUsage
+
+	Cached bool
+}
+
+// TerminationReason returns the reason why the
+// execution has been terminated.
+//
+// Timeouts take precedence over kills because the
+// sandbox is killed after the execution timed out.
+func (t RunResult) TerminationReason() models.TerminationReason {
+	switch {
+	case t.TimedOut:
+		return models.TerminationTimedOut
+	case t.OOMKilled:
+		return models.TerminationOOMKilled
+	case t.Killed:
+		return models.TerminationKilled
+	case t.BuildFailed:
+		return models.TerminationBuildFailed
+	default:
+		return models.TerminationExited
+	}
+}
+
+// ToModel returns the result together with the
+// given output and execution time as
+// ExecutionResponse model.
+func (t RunResult) ToModel(stdout, stderr string, execTime time.Duration) *models.ExecutionResponse {
+	res := &models.ExecutionResponse{
+		StdOut:             stdout,
+		StdErr:             stderr,
+		ExecTimeMS:         int(execTime.Milliseconds()),
+		ExitCode:           t.ExitCode,
+		TerminationReason:  t.TerminationReason(),
+		Artifacts:          t.Artifacts,
+		ArtifactsTruncated: t.ArtifactsTruncated,
+		Usage:              t.Usage,
+		Cached:             t.Cached,
+	}
+	if t.Build != nil {
+		res.BuildResponse = t.Build.ToModel()
+	}
+	return res
+}
+
+// BuildResult wraps the final state, the output
+// and the execution time of the build phase of
+// an execution.
+type BuildResult struct {
+	RunResult
+
+	StdOut   string
+	StdErr   string
+	ExecTime time.Duration
+}
+
+// Succeeded returns true if the build exited
+// on its own with exit code 0.
+func (t BuildResult) Succeeded() bool {
+	return t.ExitCode == 0 && t.TerminationReason() == models.TerminationExited
+}
+
+// ToModel returns the build result as
+// BuildResponse model.
+func (t BuildResult) ToModel() *models.BuildResponse {
+	return &models.BuildResponse{
+		BuildStdOut:            t.StdOut,
+		BuildStdErr:            t.StdErr,
+		BuildExitCode:          t.ExitCode,
+		BuildTimeMS:            int(t.ExecTime.Milliseconds()),
+		BuildTerminationReason: t.TerminationReason(),
+	}
+}
@@ -12,10 +12,10 @@ import (
 var argRx = regexp.MustCompile(`(?:[^\s"]+|"[^"]*")+`)
 
 // RunSpec wraps a spec and extends runtime
-// information like arguments and environment variables
-// passed to the sandbox as well as the sub directory and
-// host dir used to inject the code snippet into
-// the sandbox.
+// information like arguments, environment variables,
+// resource limits and the user passed to the sandbox
+// as well as the sub directory and host dir used to
+// inject the code snippet into the sandbox.
 type RunSpec struct {
 	models.Spec
 
@@ -23,6 +23,9 @@ type RunSpec struct {
 	Environment map[string]string `json:"environment,omitempty" yaml:"environment,omitempty"`
 	Subdir      string            `json:"subdir,omitempty" yaml:"subdir,omitempty"`
 	HostDir     string            `json:"hostdir,omitempty" yaml:"hostdir,omitempty"`
+	Limits      ResourceLimits    `json:"limits,omitempty" yaml:"limits,omitempty"`
+	UID         int               `json:"uid,omitempty" yaml:"uid,omitempty"`
+	GID         int               `json:"gid,omitempty" yaml:"gid,omitempty"`
 }
 
 // GetAssembledHostDir returns the joined directory
@@ -2,10 +2,16 @@ package sandbox
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ranna-go/ranna/pkg/models"
 )
 
+// ErrStdinClosed is returned when writing to the
+// stdin stream of a sandbox which is not running
+// or whose stdin stream has already been closed.
+var ErrStdinClosed = errors.New("stdin is closed")
+
 // Sandbox defines an interface to control an encapsulated
 // code execution environment.
 type Sandbox interface {
@@ -14,13 +20,34 @@ type Sandbox interface {
 	ID() string
 
 	// Run starts the execution of the sandbox
-	// blocking and returns the execution response
-	// information.
+	// blocking and returns the final state of
+	// the execution.
+	//
+	// The stdout and stderr streams of the sandbox
+	// are written into cOut and cErr. If cStats is
+	// not nil, the resource usage is periodically
+	// written into it while the sandbox is running.
 	//
-	// bufferCap defines the maximum size of the
-	// output stream buffers used to capture the
-	// sandbox stdout and stderr streams.
-	Run(ctx context.Context, cOut chan []byte, cErr chan []byte) (err error)
+	// When ctx is canceled before the execution
+	// finished, the sandbox is stopped and its state
+	// is returned together with the cause of the
+	// cancellation as error.
+	Run(
+		ctx context.Context,
+		cOut chan []byte,
+		cErr chan []byte,
+		cStats chan models.ResourceUsage,
+	) (res *RunResult, err error)
+
+	// WriteStdin writes p to the stdin stream of
+	// the sandbox. Data written before the sandbox
+	// is running is passed as soon as it has been
+	// started.
+	WriteStdin(p []byte) error
+
+	// CloseStdin closes the stdin stream of the
+	// running sandbox.
+	CloseStdin() error
 
 	// IsRunning returns true if the sandbox is
 	// still executing.
@@ -49,6 +76,10 @@ type Provider interface {
 	// This pulls images used in specs, for example.
 	Prepare(ctx context.Context, spec models.Spec, force bool) error
 
+	// ImageDigest returns the digest identifying the
+	// current version of the given image.
+	ImageDigest(ctx context.Context, image string) (string, error)
+
 	// CreateSandbox creates a new sandbox by given spec,
 	// allocates necessary resources for the sandbox and
 	// prepare it to be run.
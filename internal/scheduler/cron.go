@@ -2,29 +2,64 @@ package scheduler
 
 import (
 	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/robfig/cron/v3"
+
+	"github.com/ranna-go/ranna/pkg/models"
 )
 
+// job holds the state of a scheduled job.
+type job struct {
+	name string
+	spec string
+
+	running      bool
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastErr      error
+}
+
 // CronScheduler implements scheduler using
 // a cron-like schedule spec syntax.
 type CronScheduler struct {
 	c *cron.Cron
+
+	mtx  sync.Mutex
+	jobs map[cron.EntryID]*job
 }
 
 // NewCronScheduler returns a new CronScheduler instance.
 func NewCronScheduler() *CronScheduler {
 	return &CronScheduler{
-		c: cron.New(),
+		c:    cron.New(),
+		jobs: make(map[cron.EntryID]*job),
 	}
 }
 
-func (t *CronScheduler) Schedule(spec any, job func()) (id any, err error) {
+// Schedule schedules the job under the given name.
+// The state of the last run of the job, including
+// the returned error, is reported by Jobs.
+func (t *CronScheduler) Schedule(name string, spec any, fn func() error) (id any, err error) {
 	specStr, ok := spec.(string)
 	if !ok {
 		return nil, errors.New("invalid spec type: must be a string")
 	}
-	return t.c.AddFunc(specStr, job)
+
+	j := &job{name: name, spec: specStr}
+	cid, err := t.c.AddFunc(specStr, func() { t.run(j, fn) })
+	if err != nil {
+		return nil, err
+	}
+
+	t.mtx.Lock()
+	t.jobs[cid] = j
+	t.mtx.Unlock()
+
+	return cid, nil
 }
 
 func (t *CronScheduler) UnSchedule(id any) error {
@@ -33,9 +68,48 @@ func (t *CronScheduler) UnSchedule(id any) error {
 		return errors.New("invalid id type")
 	}
 	t.c.Remove(cid)
+
+	t.mtx.Lock()
+	delete(t.jobs, cid)
+	t.mtx.Unlock()
+
 	return nil
 }
 
+// Jobs returns the state of all scheduled
+// jobs ordered by their name.
+func (t *CronScheduler) Jobs() []models.SchedulerJob {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	jobs := make([]models.SchedulerJob, 0, len(t.jobs))
+	for cid, j := range t.jobs {
+		sj := models.SchedulerJob{
+			Name:    j.name,
+			Spec:    j.spec,
+			Running: j.running,
+		}
+		if next := t.c.Entry(cid).Next; !next.IsZero() {
+			sj.NextRun = &next
+		}
+		if !j.lastRun.IsZero() {
+			lastRun := j.lastRun
+			sj.LastRun = &lastRun
+			sj.LastDurationMS = j.lastDuration.Milliseconds()
+			if j.lastErr != nil {
+				sj.LastError = j.lastErr.Error()
+			}
+		}
+		jobs = append(jobs, sj)
+	}
+
+	slices.SortFunc(jobs, func(a, b models.SchedulerJob) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return jobs
+}
+
 func (t *CronScheduler) Start() {
 	t.c.Start()
 }
@@ -43,3 +117,21 @@ func (t *CronScheduler) Start() {
 func (t *CronScheduler) Stop() {
 	t.c.Stop()
 }
+
+// run runs fn and records the state
+// of the run in j.
+func (t *CronScheduler) run(j *job, fn func() error) {
+	start := time.Now()
+	t.mtx.Lock()
+	j.running = true
+	t.mtx.Unlock()
+
+	err := fn()
+
+	t.mtx.Lock()
+	j.running = false
+	j.lastRun = start
+	j.lastDuration = time.Since(start)
+	j.lastErr = err
+	t.mtx.Unlock()
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJobs(t *testing.T) {
+	s := NewCronScheduler()
+
+	_, err := s.Schedule("b", "@every 1h", func() error { return errors.New("failed") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Schedule("a", "@every 1h", func() error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Schedule("c", 1, func() error { return nil }); err == nil {
+		t.Error("expected error for invalid spec type")
+	}
+
+	jobs := s.Jobs()
+	if len(jobs) != 2 || jobs[0].Name != "a" || jobs[1].Name != "b" {
+		t.Fatalf("invalid jobs: %+v", jobs)
+	}
+	if jobs[0].LastRun != nil {
+		t.Errorf("job has not been run but has a last run: %+v", jobs[0])
+	}
+
+	for _, e := range s.c.Entries() {
+		e.Job.Run()
+	}
+
+	jobs = s.Jobs()
+	if jobs[0].LastRun == nil || jobs[0].LastError != "" {
+		t.Errorf("invalid state of succeeded job: %+v", jobs[0])
+	}
+	if jobs[1].LastRun == nil || jobs[1].LastError != "failed" {
+		t.Errorf("invalid state of failed job: %+v", jobs[1])
+	}
+}
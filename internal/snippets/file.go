@@ -0,0 +1,124 @@
+package snippets
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+const fileExtension = ".json"
+
+// FileStore implements Store by keeping each
+// snippet as JSON file in a directory.
+type FileStore struct {
+	dir string
+
+	mtx         sync.Mutex
+	lastCleanup time.Time
+}
+
+// NewFileStore returns a new instance of FileStore
+// storing snippets in dir, which is created if it
+// does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, os.ModeDir|0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (t *FileStore) Put(s *models.Snippet) error {
+	if !isValidID(s.ID) {
+		return ErrInvalidID
+	}
+
+	t.cleanup(time.Now())
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	// The snippet is written to a temporary file first
+	// so that partially written snippets are never read.
+	f, err := os.CreateTemp(t.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), t.path(s.ID))
+}
+
+func (t *FileStore) Get(id string) (s *models.Snippet, ok bool, err error) {
+	if !isValidID(id) {
+		return nil, false, nil
+	}
+
+	data, err := os.ReadFile(t.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = json.Unmarshal(data, &s); err != nil {
+		return nil, false, err
+	}
+	if s.IsExpired(time.Now()) {
+		return nil, false, nil
+	}
+
+	return s, true, nil
+}
+
+func (t *FileStore) path(id string) string {
+	return filepath.Join(t.dir, id+fileExtension)
+}
+
+// cleanup removes the files of all expired
+// snippets, at most once per cleanup interval.
+func (t *FileStore) cleanup(now time.Time) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if now.Sub(t.lastCleanup) < cleanupInterval {
+		return
+	}
+	t.lastCleanup = now
+
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), fileExtension)
+		if !ok || !isValidID(id) {
+			continue
+		}
+		data, err := os.ReadFile(t.path(id))
+		if err != nil {
+			continue
+		}
+		var s models.Snippet
+		if json.Unmarshal(data, &s) == nil && s.IsExpired(now) {
+			os.Remove(t.path(id))
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package snippets
+
+import (
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+type SpecProvider interface {
+	Spec() *spec.SafeSpecMap
+}
+
+// Store defines an interface to persist
+// code snippets.
+type Store interface {
+
+	// Put stores the given snippet.
+	Put(s *models.Snippet) error
+
+	// Get returns the snippet with the given ID.
+	// If no snippet has been found or the snippet
+	// has expired, ok is false.
+	Get(id string) (s *models.Snippet, ok bool, err error)
+}
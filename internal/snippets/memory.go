@@ -0,0 +1,61 @@
+package snippets
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+const cleanupInterval = 1 * time.Minute
+
+// MemoryStore implements Store by keeping
+// snippets in memory.
+type MemoryStore struct {
+	mtx         sync.Mutex
+	snippets    map[string]models.Snippet
+	lastCleanup time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		snippets: make(map[string]models.Snippet),
+	}
+}
+
+func (t *MemoryStore) Put(s *models.Snippet) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.cleanup(time.Now())
+	t.snippets[s.ID] = *s
+
+	return nil
+}
+
+func (t *MemoryStore) Get(id string) (s *models.Snippet, ok bool, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	snippet, ok := t.snippets[id]
+	if !ok || snippet.IsExpired(time.Now()) {
+		return nil, false, nil
+	}
+
+	return &snippet, true, nil
+}
+
+// cleanup removes all expired snippets, at
+// most once per cleanup interval.
+func (t *MemoryStore) cleanup(now time.Time) {
+	if now.Sub(t.lastCleanup) < cleanupInterval {
+		return
+	}
+	t.lastCleanup = now
+
+	for id, s := range t.snippets {
+		if s.IsExpired(now) {
+			delete(t.snippets, id)
+		}
+	}
+}
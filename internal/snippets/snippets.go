@@ -0,0 +1,182 @@
+package snippets
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+	"github.com/ranna-go/ranna/pkg/random"
+)
+
+const (
+	idLength     = 8
+	maxIDRetries = 5
+)
+
+var (
+	ErrNotFound            = errors.New("snippet not found")
+	ErrInvalidID           = errors.New("invalid snippet ID")
+	ErrEmptyCode           = errors.New("code is empty")
+	ErrUnsupportedLanguage = errors.New("unsupported language spec")
+	ErrTooLarge            = errors.New("snippet exceeds the maximum size")
+	ErrInvalidExpiry       = errors.New("invalid snippet expiry")
+	errIDCollision         = errors.New("could not generate unique snippet ID")
+)
+
+// Manager creates and provides code snippets
+// stored in a Store.
+type Manager struct {
+	store Store
+	spec  SpecProvider
+	cfg   ConfigProvider
+}
+
+// NewManager returns a new instance of Manager.
+func NewManager(store Store, spec SpecProvider, cfg ConfigProvider) *Manager {
+	return &Manager{
+		store: store,
+		spec:  spec,
+		cfg:   cfg,
+	}
+}
+
+// Create validates and stores a new snippet from
+// the given request.
+//
+// If no expiry is requested, the default expiry
+// from the config is applied.
+func (t *Manager) Create(req *models.SnippetRequest) (s *models.Snippet, err error) {
+	if req.Code == "" {
+		return nil, ErrEmptyCode
+	}
+	if _, _, ok := t.spec.Spec().Resolve(req.Language); !ok {
+		return nil, ErrUnsupportedLanguage
+	}
+	if err = t.checkSize(req); err != nil {
+		return nil, err
+	}
+
+	expires, err := t.expiry(req.ExpiresSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := t.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	s = &models.Snippet{
+		ID:          id,
+		Language:    req.Language,
+		Code:        req.Code,
+		Arguments:   req.Arguments,
+		Environment: req.Environment,
+		Created:     time.Now(),
+	}
+	if expires > 0 {
+		exp := s.Created.Add(expires)
+		s.Expires = &exp
+	}
+
+	if err = t.store.Put(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get returns the snippet with the given ID.
+func (t *Manager) Get(id string) (*models.Snippet, error) {
+	s, ok, err := t.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s, nil
+}
+
+// checkSize checks that the total size of the code,
+// arguments and environment of req does not exceed
+// the maximum size set in the config. A maximum
+// size of 0 means no limit.
+func (t *Manager) checkSize(req *models.SnippetRequest) error {
+	maxSize, err := util.ParseMemoryStr(t.cfg.Config().Snippets.MaxSize)
+	if err != nil {
+		return err
+	}
+
+	size := int64(len(req.Language) + len(req.Code))
+	for _, arg := range req.Arguments {
+		size += int64(len(arg))
+	}
+	for k, v := range req.Environment {
+		size += int64(len(k) + len(v))
+	}
+
+	if maxSize > 0 && size > maxSize {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// expiry returns the duration after which a snippet
+// expires. A duration of 0 means no expiry. If no
+// expiry is passed, the default expiry or, if not
+// set, the maximum expiry is used.
+func (t *Manager) expiry(seconds int) (time.Duration, error) {
+	cfg := t.cfg.Config().Snippets
+
+	if seconds < 0 {
+		return 0, ErrInvalidExpiry
+	}
+	if seconds == 0 {
+		seconds = cfg.DefaultExpireSeconds
+	}
+	if seconds == 0 {
+		seconds = cfg.MaxExpireSeconds
+	}
+	if cfg.MaxExpireSeconds > 0 && seconds > cfg.MaxExpireSeconds {
+		return 0, ErrInvalidExpiry
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// newID returns a random ID which is not
+// used by any stored snippet.
+func (t *Manager) newID() (string, error) {
+	for range maxIDRetries {
+		id, err := random.GetRandBase64Str(idLength)
+		if err != nil {
+			return "", err
+		}
+		_, ok, err := t.store.Get(id)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return id, nil
+		}
+	}
+	return "", errIDCollision
+}
+
+// isValidID returns true if id only consists
+// of characters produced by the ID generator.
+func isValidID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package snippets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/spec"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type staticConfig struct {
+	cfg *config.Config
+}
+
+func (t staticConfig) Config() *config.Config {
+	return t.cfg
+}
+
+type specMapProvider struct {
+	m *spec.SafeSpecMap
+}
+
+func (t specMapProvider) Spec() *spec.SafeSpecMap { return t.m }
+
+func newTestManager(store Store, cfg config.Snippets) *Manager {
+	specs := specMapProvider{spec.NewSafeSpecMap(models.SpecMap{
+		"python": {Image: "python", FileName: "main.py"},
+	})}
+	return NewManager(store, specs, staticConfig{&config.Config{Snippets: cfg}})
+}
+
+func testManager(t *testing.T, store Store) {
+	mgr := newTestManager(store, config.Snippets{MaxSize: "1K", MaxExpireSeconds: 60})
+
+	// Without a default expiry, the maximum
+	// expiry applies to the snippet.
+	s, err := mgr.Create(&models.SnippetRequest{Language: "python", Code: "print('hello')"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Expires == nil || s.Expires.After(time.Now().Add(60*time.Second)) {
+		t.Errorf("invalid expiry of snippet without expiry: %v", s.Expires)
+	}
+
+	s, err = mgr.Create(&models.SnippetRequest{
+		Language:       "python",
+		Code:           "print('hello')",
+		Arguments:      []string{"arg"},
+		Environment:    map[string]string{"KEY": "value"},
+		ExpiresSeconds: 30,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.ID) != idLength || s.Expires == nil {
+		t.Errorf("invalid snippet: %+v", s)
+	}
+
+	got, err := mgr.Get(s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != s.Code || got.Arguments[0] != "arg" || got.Environment["KEY"] != "value" {
+		t.Errorf("invalid stored snippet: %+v", got)
+	}
+
+	expectErr := func(req *models.SnippetRequest, expected error) {
+		t.Helper()
+		if _, err := mgr.Create(req); !errors.Is(err, expected) {
+			t.Errorf("invalid error: %v (expected: %v)", err, expected)
+		}
+	}
+	expectErr(&models.SnippetRequest{Language: "python", ExpiresSeconds: 30}, ErrEmptyCode)
+	expectErr(&models.SnippetRequest{Language: "cobol", Code: "code", ExpiresSeconds: 30}, ErrUnsupportedLanguage)
+	expectErr(&models.SnippetRequest{Language: "python", Code: strings.Repeat("a", 2048), ExpiresSeconds: 30}, ErrTooLarge)
+	expectErr(&models.SnippetRequest{Language: "python", Code: "code", ExpiresSeconds: 120}, ErrInvalidExpiry)
+
+	if _, err = mgr.Get("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("invalid error for unknown snippet: %v", err)
+	}
+	if _, err = mgr.Get("../../etc/passwd"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("invalid error for invalid ID: %v", err)
+	}
+
+	expired := time.Now().Add(-time.Second)
+	if err = store.Put(&models.Snippet{ID: "expired", Language: "python", Expires: &expired}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = mgr.Get("expired"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("invalid error for expired snippet: %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testManager(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testManager(t, store)
+}
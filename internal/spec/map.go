@@ -27,21 +27,24 @@ func NewSafeSpecMap(m models.SpecMap) (t *SafeSpecMap) {
 // spec property), get is performed with the value
 // of 'use' as key and isAlias as true to prevent alias
 // cycles.
-func (t *SafeSpecMap) get(key string, isAlias bool) (s models.Spec, ok bool) {
+//
+// Alongside the spec, the key of the resolved spec
+// is returned.
+func (t *SafeSpecMap) get(key string, isAlias bool) (s models.Spec, resolvedKey string, ok bool) {
 	_sp, _ := t.m.Load(key)
 	sp, ok := _sp.(*models.Spec)
 	if !ok {
-		return s, false
+		return s, "", false
 	}
 
 	if sp.Use != "" {
 		if isAlias {
-			return s, false
+			return s, "", false
 		}
 		return t.get(sp.Use, true)
 	}
 
-	return *sp, true
+	return *sp, key, true
 }
 
 // Get tries to retrieve a Spec from the internal
@@ -49,8 +52,17 @@ func (t *SafeSpecMap) get(key string, isAlias bool) (s models.Spec, ok bool) {
 // a nil map and false is returned.
 //
 // This also resolved aliases (see 'use' spec property).
-func (t *SafeSpecMap) Get(key string) (models.Spec, bool) {
-	return t.get(key, false)
+func (t *SafeSpecMap) Get(key string) (s models.Spec, ok bool) {
+	s, _, ok = t.get(key, false)
+	return s, ok
+}
+
+// Resolve works like Get but also returns the key
+// of the resolved spec, which differs from the passed
+// key if it is an alias.
+func (t *SafeSpecMap) Resolve(key string) (resolvedKey string, s models.Spec, ok bool) {
+	s, resolvedKey, ok = t.get(key, false)
+	return resolvedKey, s, ok
 }
 
 // GetSnapshot initializes a new SpecMap from the current
@@ -0,0 +1,7 @@
+package tracing
+
+import "github.com/ranna-go/ranna/internal/config"
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ranna-go/ranna/internal/config"
+)
+
+type staticConfig struct {
+	c *config.Config
+}
+
+func (t staticConfig) Config() *config.Config { return t.c }
+
+func TestSetup(t *testing.T) {
+	for _, exporter := range []string{"", ExporterStdout, ExporterOTLP} {
+		cfg := staticConfig{&config.Config{Tracing: config.Tracing{
+			Exporter:    exporter,
+			ServiceName: "ranna",
+		}}}
+		shutdown, err := Setup(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("exporter %q: unexpected error: %v", exporter, err)
+		}
+		if err = shutdown(context.Background()); err != nil {
+			t.Fatalf("exporter %q: unexpected shutdown error: %v", exporter, err)
+		}
+	}
+
+	cfg := staticConfig{&config.Config{Tracing: config.Tracing{Exporter: "jaeger"}}}
+	if _, err := Setup(context.Background(), cfg); err == nil {
+		t.Fatal("expected error for invalid exporter")
+	}
+}
+
+func TestEnd(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "ok")
+	End(span, nil)
+	_, span = tracer.Start(context.Background(), "failed")
+	End(span, errors.New("oops"))
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 ended spans, got %d", len(spans))
+	}
+	if s := spans[0].Status(); s.Code != codes.Unset {
+		t.Errorf("expected unset status, got %v", s.Code)
+	}
+	if s := spans[1].Status(); s.Code != codes.Error || s.Description != "oops" {
+		t.Errorf("expected error status, got %v %q", s.Code, s.Description)
+	}
+	if n := len(spans[1].Events()); n != 1 {
+		t.Errorf("expected 1 recorded error event, got %d", n)
+	}
+}
@@ -0,0 +1,200 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/level"
+	"github.com/zekrotja/rogu/log"
+)
+
+const (
+	HeaderSignature = "X-Ranna-Signature"
+	HeaderDelivery  = "X-Ranna-Delivery"
+)
+
+var (
+	ErrCallbacksDisabled  = errors.New("callbacks are disabled")
+	ErrInvalidCallbackURL = errors.New("invalid callback URL")
+	ErrCallbackNotAllowed = errors.New("callback URL is not allowed")
+)
+
+// Dispatcher delivers callback payloads to the
+// callback URLs of executions.
+type Dispatcher struct {
+	cfg    ConfigProvider
+	client *http.Client
+	logger rogu.Logger
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher returns a new instance of Dispatcher.
+//
+// Redirects are not followed because their targets
+// are not checked against the allowed hosts.
+func NewDispatcher(cfg ConfigProvider) *Dispatcher {
+	return &Dispatcher{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: time.Duration(cfg.Config().Webhooks.TimeoutSeconds) * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: log.Tagged("Webhooks"),
+	}
+}
+
+// Validate checks that the given callback URL is a
+// valid HTTP(S) URL whose host is allowed by the
+// allowlist set in the config.
+func (t *Dispatcher) Validate(callbackURL string) error {
+	allowed := strings.Fields(t.cfg.Config().Webhooks.AllowedHosts)
+	if len(allowed) == 0 {
+		return ErrCallbacksDisabled
+	}
+
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidCallbackURL
+	}
+
+	if !isHostAllowed(u.Hostname(), allowed) {
+		return fmt.Errorf("%w: %s", ErrCallbackNotAllowed, u.Hostname())
+	}
+
+	return nil
+}
+
+// Send delivers the JSON encoded payload to the
+// given callback URL in the background.
+func (t *Dispatcher) Send(callbackURL string, payload any) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.logger.Error().Err(err).Field("url", callbackURL).Msg("failed encoding callback payload")
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.deliver(callbackURL, body)
+	}()
+}
+
+// Wait blocks until all pending deliveries have
+// finished or until the given context is done.
+func (t *Dispatcher) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver posts body to the callback URL. Failed
+// deliveries are retried with an exponential backoff
+// up to the number of retries set in the config.
+func (t *Dispatcher) deliver(callbackURL string, body []byte) {
+	cfg := t.cfg.Config().Webhooks
+	deliveryId := xid.New().String()
+	signature := sign(cfg.Secret, body)
+	backoff := time.Duration(cfg.BackoffMS) * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		status, err := t.post(callbackURL, deliveryId, signature, body)
+
+		lvl := level.Info
+		if err != nil || status >= 300 {
+			lvl = level.Warn
+		}
+		t.logger.WithLevel(lvl).Fields(
+			"url", callbackURL,
+			"delivery", deliveryId,
+			"attempt", attempt,
+			"status", status,
+		).Err(err).Msg("callback delivery attempt")
+
+		if err == nil && !isRetryableStatus(status) {
+			return
+		}
+		if attempt > cfg.MaxRetries {
+			t.logger.Error().Fields("url", callbackURL, "delivery", deliveryId).Msg("callback delivery failed")
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (t *Dispatcher) post(callbackURL, deliveryId, signature string, body []byte) (status int, err error) {
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, deliveryId)
+	req.Header.Set(HeaderSignature, signature)
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+
+	return res.StatusCode, nil
+}
+
+// sign returns the hex encoded HMAC-SHA256
+// signature of body prefixed with "sha256=".
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isHostAllowed returns true if host matches any of
+// the allowed hosts. Entries starting with "*." match
+// all subdomains of the following domain.
+func isHostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == "*" || a == host {
+			return true
+		}
+		if domain, ok := strings.CutPrefix(a, "*."); ok && strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryableStatus returns true if the delivery
+// should be retried after a response with the given
+// status code.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
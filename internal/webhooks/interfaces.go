@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+type SandboxManager interface {
+	RunInSandbox(
+		ctx context.Context,
+		req *models.ExecutionRequest,
+		chans sandbox.RunChannels,
+	) (res *sandbox.RunResult, err error)
+	PrepareEnvironments(ctx context.Context, force bool) []error
+	KillAndCleanUp(ctx context.Context, id string) (bool, error)
+	WriteStdin(id string, p []byte) (bool, error)
+	CloseStdin(id string) (bool, error)
+	Cleanup(ctx context.Context) []error
+	GetProvider() sandbox.Provider
+	PoolInfo() map[string]models.PoolInfo
+}
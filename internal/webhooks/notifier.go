@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/internal/util"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// Notifier wraps a sandbox manager and sends the
+// results of executions with a callback URL to the
+// callback URL when they have finished.
+type Notifier struct {
+	SandboxManager
+
+	dispatcher *Dispatcher
+	cfg        ConfigProvider
+}
+
+// NewNotifier returns a new instance of Notifier
+// delivering callbacks using dispatcher.
+func NewNotifier(manager SandboxManager, dispatcher *Dispatcher, cfg ConfigProvider) *Notifier {
+	return &Notifier{
+		SandboxManager: manager,
+		dispatcher:     dispatcher,
+		cfg:            cfg,
+	}
+}
+
+// RunInSandbox runs the given request using the
+// wrapped manager. If the request has a callback
+// URL, it is validated before and the result is
+// sent to it after the execution.
+func (t *Notifier) RunInSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	chans sandbox.RunChannels,
+) (res *sandbox.RunResult, err error) {
+	if req.CallbackURL == "" {
+		return t.SandboxManager.RunInSandbox(ctx, req, chans)
+	}
+
+	if err = t.dispatcher.Validate(req.CallbackURL); err != nil {
+		return nil, sandbox.NewRejectedError(err)
+	}
+
+	bufferCap, err := util.ParseMemoryStr(t.cfg.Config().Sandbox.StreamBufferCap)
+	if err != nil {
+		return nil, err
+	}
+
+	tee, teeChans := sandbox.NewOutputTee(chans, int(bufferCap))
+	execTime := util.MeasureTime(func() {
+		res, err = t.SandboxManager.RunInSandbox(ctx, req, teeChans)
+	})
+	stdOut, stdErr := tee.Close()
+
+	payload := &models.CallbackPayload{RunId: tee.RunId()}
+	if err != nil {
+		payload.Error = sandbox.ErrorModel(err)
+	} else {
+		payload.Result = res.ToModel(stdOut, stdErr, execTime)
+	}
+	t.dispatcher.Send(req.CallbackURL, payload)
+
+	return res, err
+}
@@ -0,0 +1,231 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ranna-go/ranna/internal/config"
+	"github.com/ranna-go/ranna/internal/sandbox"
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+type staticConfig struct {
+	cfg *config.Config
+}
+
+func (t staticConfig) Config() *config.Config {
+	return t.cfg
+}
+
+// fakeSandboxManager runs executions which
+// print their code.
+type fakeSandboxManager struct {
+	SandboxManager
+}
+
+func (t fakeSandboxManager) RunInSandbox(
+	ctx context.Context,
+	req *models.ExecutionRequest,
+	chans sandbox.RunChannels,
+) (*sandbox.RunResult, error) {
+	chans.Spawn <- "run"
+	chans.Stdout <- []byte(req.Code)
+	return &sandbox.RunResult{ExitCode: 1}, nil
+}
+
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver responds to callback deliveries with
+// the given status codes in order.
+type receiver struct {
+	mtx        sync.Mutex
+	statuses   []int
+	deliveries []delivery
+}
+
+func (t *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	status := http.StatusOK
+	if i := len(t.deliveries); i < len(t.statuses) {
+		status = t.statuses[i]
+	}
+	t.deliveries = append(t.deliveries, delivery{r.Header, body})
+	w.WriteHeader(status)
+}
+
+func newTestConfig(allowedHosts string) staticConfig {
+	return staticConfig{&config.Config{
+		Sandbox: config.Sandbox{StreamBufferCap: "1M"},
+		Webhooks: config.Webhooks{
+			AllowedHosts:   allowedHosts,
+			Secret:         "secret",
+			MaxRetries:     2,
+			BackoffMS:      1,
+			TimeoutSeconds: 1,
+		},
+	}}
+}
+
+func TestValidate(t *testing.T) {
+	d := NewDispatcher(newTestConfig("example.com *.ranna.dev *zekro.de"))
+
+	expect := func(callbackURL string, expected error) {
+		t.Helper()
+		if err := d.Validate(callbackURL); !errors.Is(err, expected) {
+			t.Errorf("%s: invalid error: %v (expected: %v)", callbackURL, err, expected)
+		}
+	}
+
+	expect("https://example.com/hook", nil)
+	expect("http://EXAMPLE.com:8080/hook", nil)
+	expect("https://api.ranna.dev/hook", nil)
+	expect("https://ranna.dev/hook", ErrCallbackNotAllowed)
+	expect("https://example.org/hook", ErrCallbackNotAllowed)
+	expect("https://example.com.evil.org/hook", ErrCallbackNotAllowed)
+	expect("https://evilranna.dev/hook", ErrCallbackNotAllowed)
+	expect("https://evilzekro.de/hook", ErrCallbackNotAllowed)
+	expect("https://api.zekro.de/hook", ErrCallbackNotAllowed)
+	expect("ftp://example.com/hook", ErrInvalidCallbackURL)
+	expect("example.com", ErrInvalidCallbackURL)
+
+	d = NewDispatcher(newTestConfig(""))
+	expect("https://example.com/hook", ErrCallbacksDisabled)
+}
+
+func TestDeliver(t *testing.T) {
+	recv := &receiver{statuses: []int{
+		http.StatusInternalServerError,
+		http.StatusTooManyRequests,
+	}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	d := NewDispatcher(newTestConfig("127.0.0.1"))
+	d.Send(srv.URL, map[string]string{"hello": "world"})
+	d.Wait(context.Background())
+
+	if len(recv.deliveries) != 3 {
+		t.Fatalf("received %d deliveries (expected: 3)", len(recv.deliveries))
+	}
+	for _, dl := range recv.deliveries {
+		if sig := dl.header.Get(HeaderSignature); sig != sign("secret", dl.body) {
+			t.Errorf("invalid signature: %s", sig)
+		}
+		if id := dl.header.Get(HeaderDelivery); id != recv.deliveries[0].header.Get(HeaderDelivery) {
+			t.Errorf("delivery ID changed between retries: %s", id)
+		}
+	}
+
+	// Deliveries are not retried after the
+	// maximum number of retries.
+	recv.deliveries = nil
+	recv.statuses = []int{500, 500, 500, 500}
+	d.Send(srv.URL, "payload")
+	d.Wait(context.Background())
+	if len(recv.deliveries) != 3 {
+		t.Errorf("received %d deliveries (expected: 3)", len(recv.deliveries))
+	}
+}
+
+func TestDeliverNoRedirect(t *testing.T) {
+	recv := &receiver{}
+	target := httptest.NewServer(recv)
+	defer target.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(newTestConfig("127.0.0.1"))
+	d.Send(srv.URL, "payload")
+	if err := d.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recv.deliveries) != 0 {
+		t.Errorf("redirect has been followed %d times", len(recv.deliveries))
+	}
+}
+
+func TestWaitBounded(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	d := NewDispatcher(newTestConfig("127.0.0.1"))
+	d.Send(srv.URL, "payload")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := d.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("invalid error: %v (expected: %v)", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	recv := &receiver{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	cfg := newTestConfig("127.0.0.1")
+	d := NewDispatcher(cfg)
+	n := NewNotifier(fakeSandboxManager{}, d, cfg)
+
+	output := sandbox.NewOutputCollector(1024)
+	cSpn := make(chan string, 1)
+	_, err := n.RunInSandbox(context.Background(), &models.ExecutionRequest{
+		Code:        "hello",
+		CallbackURL: srv.URL,
+	}, sandbox.RunChannels{
+		Spawn:  cSpn,
+		Stdout: output.Stdout,
+		Stderr: output.Stderr,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout, _ := output.Close(); stdout != "hello" {
+		t.Errorf("invalid output passed through: %s", stdout)
+	}
+	if runId := <-cSpn; runId != "run" {
+		t.Errorf("invalid run ID passed through: %s", runId)
+	}
+
+	d.Wait(context.Background())
+	if len(recv.deliveries) != 1 {
+		t.Fatalf("received %d deliveries (expected: 1)", len(recv.deliveries))
+	}
+	var payload models.CallbackPayload
+	if err = json.Unmarshal(recv.deliveries[0].body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.RunId != "run" || payload.Result == nil ||
+		payload.Result.StdOut != "hello" || payload.Result.ExitCode != 1 {
+		t.Errorf("invalid payload: %+v", payload)
+	}
+
+	_, err = n.RunInSandbox(context.Background(), &models.ExecutionRequest{
+		CallbackURL: "https://example.com/hook",
+	}, sandbox.RunChannels{})
+	if !errors.Is(err, ErrCallbackNotAllowed) {
+		t.Errorf("invalid error for disallowed callback URL: %v", err)
+	}
+}
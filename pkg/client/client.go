@@ -1,6 +1,10 @@
 package client
 
-import "github.com/ranna-go/ranna/pkg/models"
+import (
+	"iter"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
 
 // Client provides an API endpoint wrapper
 // for the ranna API.
@@ -16,5 +20,32 @@ type Client interface {
 	// An error response is only returned if the request
 	// itself failed. If the executed code failed, this
 	// will only be visible in the execution response.
+	//
+	// If the client is in async mode, the execution is
+	// run as job and its state is polled until it has
+	// finished.
 	Exec(req models.ExecutionRequest) (res models.ExecutionResponse, err error)
+
+	// ExecStream sends an execution request and returns
+	// an iterator over the events of the execution as
+	// they are received.
+	//
+	// The event data is decoded into the data model of
+	// the event code, i.e. models.DataLog for log events.
+	// The last event is either a stop or an error event.
+	// If the request or decoding an event fails, the
+	// error is yielded and the iteration ends.
+	ExecStream(req models.ExecutionRequest) iter.Seq2[models.Event, error]
+
+	// CreateJob sends an execution request which is
+	// run asynchronously and returns the created job.
+	CreateJob(req models.ExecutionRequest) (job models.Job, err error)
+
+	// Job returns the current state of the job
+	// with the given ID.
+	Job(id string) (job models.Job, err error)
+
+	// CancelJob cancels the pending or running job
+	// with the given ID.
+	CancelJob(id string) (job models.Job, err error)
 }
@@ -1,12 +1,17 @@
 package client
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/ranna-go/ranna/pkg/models"
 )
 
+// ErrJobCanceled is returned when the job of an
+// execution in async mode has been canceled.
+var ErrJobCanceled = errors.New("job has been canceled")
+
 // ResponseError is an error which wraps
 // a response ErrorModel and the Response
 // object reference itself.
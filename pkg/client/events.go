@@ -0,0 +1,53 @@
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/ranna-go/ranna/pkg/models"
+)
+
+// rawEvent is an event whose data has
+// not been decoded yet.
+type rawEvent struct {
+	Code  models.EventCode `json:"code"`
+	Nonce int              `json:"nonce,omitempty"`
+	Data  json.RawMessage  `json:"data,omitempty"`
+}
+
+// decode returns the event with its data decoded
+// into the data model of the event code. The data
+// of unknown events is decoded as generic value.
+func (t rawEvent) decode() (evt models.Event, err error) {
+	evt.Code = t.Code
+	evt.Nonce = t.Nonce
+
+	if len(t.Data) == 0 {
+		return evt, nil
+	}
+
+	switch t.Code {
+	case models.EventError:
+		evt.Data, err = decodeData[models.WsError](t.Data)
+	case models.EventSpawn:
+		evt.Data, err = decodeData[models.DataSpawn](t.Data)
+	case models.EventLog:
+		evt.Data, err = decodeData[models.DataLog](t.Data)
+	case models.EventStop:
+		evt.Data, err = decodeData[models.DataStop](t.Data)
+	case models.EventBuildStart, models.EventRunStart:
+		evt.Data, err = decodeData[models.DataRunId](t.Data)
+	case models.EventBuildEnd:
+		evt.Data, err = decodeData[models.DataBuildEnd](t.Data)
+	case models.EventArtifact:
+		evt.Data, err = decodeData[models.DataArtifact](t.Data)
+	default:
+		evt.Data, err = decodeData[any](t.Data)
+	}
+
+	return evt, err
+}
+
+func decodeData[T any](data json.RawMessage) (v T, err error) {
+	err = json.Unmarshal(data, &v)
+	return v, err
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -14,16 +16,22 @@ import (
 )
 
 const (
-	defaultVersion   = "v1"
-	defaultUserAgent = "ranna/pkg/client"
+	defaultVersion      = "v1"
+	defaultUserAgent    = "ranna/pkg/client"
+	defaultPollInterval = 500 * time.Millisecond
 )
 
 // Options for the HTTP client.
+//
+// If Async is set, executions are run as jobs
+// whose state is polled in the given interval.
 type Options struct {
-	Endpoint      string `json:"endpoint"`
-	Version       string `json:"version"`
-	Authorization string `json:"authorization"`
-	UserAgent     string `json:"useragent"`
+	Endpoint      string        `json:"endpoint"`
+	Version       string        `json:"version"`
+	Authorization string        `json:"authorization"`
+	UserAgent     string        `json:"useragent"`
+	Async         bool          `json:"async"`
+	PollInterval  time.Duration `json:"pollinterval"`
 }
 
 type httpClient struct {
@@ -58,40 +66,56 @@ func checkAndDefaultOptions(options *Options) error {
 	if options.UserAgent == "" {
 		options.UserAgent = defaultUserAgent
 	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultPollInterval
+	}
 	return nil
 }
 
 func (t *httpClient) request(method, path string, body any, resData any) (err error) {
+	res, err := t.do(method, path, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return json.NewDecoder(res.Body).Decode(resData)
+}
+
+// do sends the request and returns the response. If
+// the response has an error status, it is closed and
+// a ResponseError is returned.
+func (t *httpClient) do(method, path string, body any, accept string) (res *http.Response, err error) {
 	url := fmt.Sprintf("%s/%s/%s", t.options.Endpoint, t.options.Version, path)
 
 	var bodyReader io.Reader
 	if body != nil {
 		buff := bytes.NewBuffer([]byte{})
 		if err = json.NewEncoder(buff).Encode(body); err != nil {
-			return err
+			return nil, err
 		}
 		bodyReader = buff
 	}
 
 	req, err := http.NewRequest(method, url, bodyReader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Accept", accept)
 	req.Header.Add("User-Agent", t.options.UserAgent)
 	if t.options.Authorization != "" {
 		req.Header.Add("Authorization", t.options.Authorization)
 	}
 
-	res, err := t.client.Do(req)
+	res, err = t.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer res.Body.Close()
 
 	if res.StatusCode >= 400 {
+		defer res.Body.Close()
 		resErr := &ResponseError{
 			ErrorModel: &models.ErrorModel{
 				Code:  res.StatusCode,
@@ -100,13 +124,13 @@ func (t *httpClient) request(method, path string, body any, resData any) (err er
 		}
 		if res.ContentLength > 0 && strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
 			if err = json.NewDecoder(res.Body).Decode(resErr.ErrorModel); err != nil {
-				return err
+				return nil, err
 			}
 		}
-		return resErr
+		return nil, resErr
 	}
 
-	return json.NewDecoder(res.Body).Decode(resData)
+	return res, nil
 }
 
 func (t *httpClient) Spec() (spec models.SpecMap, err error) {
@@ -115,6 +139,84 @@ func (t *httpClient) Spec() (spec models.SpecMap, err error) {
 }
 
 func (t *httpClient) Exec(req models.ExecutionRequest) (res models.ExecutionResponse, err error) {
+	if t.options.Async {
+		return t.execAsync(req)
+	}
 	err = t.request("POST", "exec", req, &res)
 	return res, err
 }
+
+func (t *httpClient) ExecStream(req models.ExecutionRequest) iter.Seq2[models.Event, error] {
+	return func(yield func(models.Event, error) bool) {
+		res, err := t.do("POST", "exec", req, "application/x-ndjson")
+		if err != nil {
+			yield(models.Event{}, err)
+			return
+		}
+		defer res.Body.Close()
+
+		dec := json.NewDecoder(res.Body)
+		for {
+			var raw rawEvent
+			if err = dec.Decode(&raw); err == io.EOF {
+				return
+			}
+			if err == nil {
+				var evt models.Event
+				if evt, err = raw.decode(); err == nil {
+					if !yield(evt, nil) {
+						return
+					}
+					continue
+				}
+			}
+			yield(models.Event{}, err)
+			return
+		}
+	}
+}
+
+func (t *httpClient) CreateJob(req models.ExecutionRequest) (job models.Job, err error) {
+	err = t.request("POST", "jobs", req, &job)
+	return job, err
+}
+
+func (t *httpClient) Job(id string) (job models.Job, err error) {
+	err = t.request("GET", "jobs/"+url.PathEscape(id), nil, &job)
+	return job, err
+}
+
+func (t *httpClient) CancelJob(id string) (job models.Job, err error) {
+	err = t.request("DELETE", "jobs/"+url.PathEscape(id), nil, &job)
+	return job, err
+}
+
+func (t *httpClient) execAsync(req models.ExecutionRequest) (res models.ExecutionResponse, err error) {
+	job, err := t.CreateJob(req)
+	if err != nil {
+		return res, err
+	}
+
+	for !job.IsDone() {
+		time.Sleep(t.options.PollInterval)
+		if job, err = t.Job(job.ID); err != nil {
+			return res, err
+		}
+	}
+
+	if job.Result != nil {
+		res = *job.Result
+	}
+
+	switch job.Status {
+	case models.JobFailed:
+		if job.Error == nil {
+			job.Error = &models.ErrorModel{Error: "unknown"}
+		}
+		err = &ResponseError{ErrorModel: job.Error}
+	case models.JobCanceled:
+		err = ErrJobCanceled
+	}
+
+	return res, err
+}
@@ -2,9 +2,11 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/ranna-go/ranna/pkg/models"
 )
@@ -53,9 +55,11 @@ func TestSpec(t *testing.T) {
 
 func TestExec(t *testing.T) {
 	testExec := &models.ExecutionResponse{
-		StdOut:     "stdput",
-		StdErr:     "stderr",
-		ExecTimeMS: 1337,
+		StdOut:            "stdput",
+		StdErr:            "stderr",
+		ExecTimeMS:        1337,
+		ExitCode:          1,
+		TerminationReason: models.TerminationExited,
 	}
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(testExec)
@@ -85,4 +89,137 @@ func TestExec(t *testing.T) {
 	if recExec.ExecTimeMS != testExec.ExecTimeMS {
 		t.Errorf("ExecTimeMS value was invalid: %d", recExec.ExecTimeMS)
 	}
+	if recExec.ExitCode != testExec.ExitCode {
+		t.Errorf("ExitCode value was invalid: %d", recExec.ExitCode)
+	}
+	if recExec.TerminationReason != testExec.TerminationReason {
+		t.Errorf("TerminationReason value was invalid: %s", recExec.TerminationReason)
+	}
+}
+
+func TestExecAsync(t *testing.T) {
+	testExec := &models.ExecutionResponse{
+		StdOut:            "stdout",
+		TerminationReason: models.TerminationExited,
+	}
+	polls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/jobs":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(models.Job{ID: "job", Status: models.JobPending})
+		case r.Method == "GET" && r.URL.Path == "/v1/jobs/job":
+			polls++
+			job := models.Job{ID: "job", Status: models.JobRunning}
+			if polls == 2 {
+				job.Status = models.JobFinished
+				job.Result = testExec
+			}
+			json.NewEncoder(w).Encode(job)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := New(Options{
+		Endpoint:     ts.URL,
+		Async:        true,
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recExec, err := client.Exec(models.ExecutionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 2 {
+		t.Errorf("job has been polled %d times (expected: 2)", polls)
+	}
+	if recExec.StdOut != testExec.StdOut {
+		t.Errorf("StdOut value was invalid: %s", recExec.StdOut)
+	}
+}
+
+func TestExecStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "application/x-ndjson" {
+			t.Errorf("invalid accept header: %s", accept)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		enc.Encode(models.Event{Code: models.EventSpawn, Data: models.DataSpawn{
+			DataRunId: models.DataRunId{RunId: "run"}}})
+		enc.Encode(models.Event{Code: models.EventBuildStart, Data: models.DataRunId{RunId: "run"}})
+		enc.Encode(models.Event{Code: models.EventBuildEnd, Data: models.DataBuildEnd{
+			DataRunId: models.DataRunId{RunId: "run"}, BuildResponse: models.BuildResponse{BuildStdOut: "built"}}})
+		enc.Encode(models.Event{Code: models.EventRunStart, Data: models.DataRunId{RunId: "run"}})
+		enc.Encode(models.Event{Code: models.EventLog, Data: models.DataLog{
+			DataRunId: models.DataRunId{RunId: "run"}, StdOut: "hello"}})
+		w.Write([]byte("\n"))
+		enc.Encode(models.Event{Code: models.EventStop, Data: models.DataStop{
+			DataRunId: models.DataRunId{RunId: "run"}, ExitCode: 1}})
+	}))
+	defer ts.Close()
+
+	client, err := New(Options{Endpoint: ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []models.Event
+	for evt, err := range client.ExecStream(models.ExecutionRequest{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, evt)
+	}
+
+	if len(events) != 6 {
+		t.Fatalf("received %d events (expected: 6)", len(events))
+	}
+	if data, ok := events[1].Data.(models.DataRunId); !ok || data.RunId != "run" {
+		t.Errorf("invalid build start event: %+v", events[1])
+	}
+	if data, ok := events[2].Data.(models.DataBuildEnd); !ok || data.BuildStdOut != "built" {
+		t.Errorf("invalid build end event: %+v", events[2])
+	}
+	if data, ok := events[3].Data.(models.DataRunId); !ok || data.RunId != "run" {
+		t.Errorf("invalid run start event: %+v", events[3])
+	}
+	if data, ok := events[4].Data.(models.DataLog); !ok || data.StdOut != "hello" {
+		t.Errorf("invalid log event: %+v", events[4])
+	}
+	if data, ok := events[5].Data.(models.DataStop); !ok || data.ExitCode != 1 || data.RunId != "run" {
+		t.Errorf("invalid stop event: %+v", events[5])
+	}
+}
+
+func TestExecStreamError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorModel{Code: 400, Error: "code is empty"})
+	}))
+	defer ts.Close()
+
+	client, err := New(Options{Endpoint: ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for _, err := range client.ExecStream(models.ExecutionRequest{}) {
+		n++
+		var resErr *ResponseError
+		if !errors.As(err, &resErr) || resErr.ErrorModel.Error != "code is empty" {
+			t.Errorf("invalid error: %v", err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("yielded %d values (expected: 1)", n)
+	}
 }
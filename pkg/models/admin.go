@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// RunningSandbox describes an execution
+// with a running sandbox.
+//
+// RunId identifies the execution across all of its
+// phases and is used to kill it. SandboxId is the
+// ID of the currently running sandbox.
+type RunningSandbox struct {
+	RunId     string    `json:"runid"`
+	SandboxId string    `json:"sandboxid"`
+	Language  string    `json:"language"`
+	Client    string    `json:"client"`
+	Started   time.Time `json:"started"`
+}
+
+// SchedulerJob describes the state of
+// a scheduled background job.
+//
+// LastRun, LastDurationMS and LastError are
+// only set when the job has been run.
+type SchedulerJob struct {
+	Name           string     `json:"name"`
+	Spec           string     `json:"spec"`
+	Running        bool       `json:"running"`
+	NextRun        *time.Time `json:"next_run,omitempty"`
+	LastRun        *time.Time `json:"last_run,omitempty"`
+	LastDurationMS int64      `json:"last_duration_ms,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+}
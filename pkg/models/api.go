@@ -2,20 +2,131 @@ package models
 
 // ExecutionRequest is the execution
 // request model.
+//
+// Files maps additional file paths, relative to the
+// working directory of the execution, to their content.
+// The code is written to the file specified by the
+// spec's file name, which acts as entry point. If no
+// code is passed, the entry point file must be part
+// of Files.
+//
+// Memory, TimeoutSeconds and CPUs can be passed to
+// override the default resource limits of the sandbox
+// within the maximums allowed by the server.
+//
+// Stdin is written to the stdin stream of the
+// executed program. Afterwards, the stream is
+// closed unless Interactive is set, which is only
+// supported by the WebSocket API. Then, further
+// input can be streamed using the STDIN operation.
+//
+// Artifacts contains glob patterns, relative to the
+// working directory, of files which are returned
+// after the execution has finished.
+//
+// If LiveStats is set, the resource usage of the
+// execution is sent periodically while it is running,
+// which is only supported by the WebSocket API.
+//
+// If CallbackURL is set, the result is posted to
+// the given URL when the execution has finished.
+// The URL must be allowed by the server.
 type ExecutionRequest struct {
 	Language         string            `json:"language"`
 	Code             string            `json:"code"`
 	InlineExpression bool              `json:"inline_expression"`
 	Arguments        []string          `json:"arguments"`
 	Environment      map[string]string `json:"environment"`
+	Files            map[string]string `json:"files,omitempty"`
+	Stdin            string            `json:"stdin,omitempty"`
+	Interactive      bool              `json:"interactive,omitempty"`
+	Memory           string            `json:"memory,omitempty"`
+	TimeoutSeconds   int               `json:"timeout_seconds,omitempty"`
+	CPUs             float64           `json:"cpus,omitempty"`
+	Artifacts        []string          `json:"artifacts,omitempty"`
+	LiveStats        bool              `json:"live_stats,omitempty"`
+	CallbackURL      string            `json:"callback_url,omitempty"`
+}
+
+// TerminationReason describes why an
+// execution has been terminated.
+type TerminationReason string
+
+const (
+	// TerminationExited is set when the executed
+	// program exited on its own.
+	TerminationExited TerminationReason = "exited"
+	// TerminationTimedOut is set when the execution
+	// exceeded the configured timeout.
+	TerminationTimedOut TerminationReason = "timed_out"
+	// TerminationOOMKilled is set when the execution
+	// has been killed because it exceeded the memory
+	// limit.
+	TerminationOOMKilled TerminationReason = "oom_killed"
+	// TerminationKilled is set when the execution
+	// has been killed manually.
+	TerminationKilled TerminationReason = "killed"
+	// TerminationBuildFailed is set when the run
+	// phase has been skipped because the build
+	// phase exited with a non-zero exit code.
+	TerminationBuildFailed TerminationReason = "build_failed"
+)
+
+// BuildResponse wraps the output and the final
+// state of the build phase of an execution.
+type BuildResponse struct {
+	BuildStdOut            string            `json:"build_stdout"`
+	BuildStdErr            string            `json:"build_stderr"`
+	BuildExitCode          int               `json:"build_exit_code"`
+	BuildTimeMS            int               `json:"build_time_ms"`
+	BuildTerminationReason TerminationReason `json:"build_termination_reason"`
+}
+
+// Artifact wraps a file produced by an execution.
+// Data is encoded as base64 in JSON.
+type Artifact struct {
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+	Data     []byte `json:"data"`
+}
+
+// ResourceUsage wraps the resources used by an
+// execution. The values are sampled once right after
+// the start and periodically while the execution is
+// running, so usage after the last sample is missing.
+type ResourceUsage struct {
+	PeakMemoryBytes   int64 `json:"peak_memory_bytes"`
+	CPUTimeMS         int64 `json:"cpu_time_ms"`
+	BlockIOReadBytes  int64 `json:"blkio_read_bytes"`
+	BlockIOWriteBytes int64 `json:"blkio_write_bytes"`
+	PeakPids          int64 `json:"peak_pids"`
 }
 
 // ExecutionResponse is the response
 // model received on execution request.
+//
+// The build fields are only present if the
+// spec defines a build phase.
+//
+// ArtifactsTruncated is set if not all files
+// matching the requested artifact patterns have
+// been returned because of the server's limits.
+//
+// Cached is set if the response has been served
+// from the result cache without running the code.
 type ExecutionResponse struct {
-	StdOut     string `json:"stdout"`
-	StdErr     string `json:"stderr"`
-	ExecTimeMS int    `json:"exectimems"`
+	*BuildResponse
+
+	StdOut             string            `json:"stdout"`
+	StdErr             string            `json:"stderr"`
+	ExecTimeMS         int               `json:"exectimems"`
+	ExitCode           int               `json:"exit_code"`
+	TerminationReason  TerminationReason `json:"termination_reason"`
+	Artifacts          []Artifact        `json:"artifacts,omitempty"`
+	ArtifactsTruncated bool              `json:"artifacts_truncated,omitempty"`
+	Usage              *ResourceUsage    `json:"usage,omitempty"`
+	Cached             bool              `json:"cached,omitempty"`
 }
 
 // SandboxInfo wraps information about the
@@ -25,11 +136,19 @@ type SandboxInfo struct {
 	Version string `json:"version"`
 }
 
+// PoolInfo wraps the occupancy of the pool
+// of pre-created sandboxes of a spec.
+type PoolInfo struct {
+	Size      int `json:"size"`
+	Available int `json:"available"`
+}
+
 // SystemInfo wraps general information about
 // the ranna deployment.
 type SystemInfo struct {
-	Version     string       `json:"version"`
-	BuildDate   string       `json:"builddate"`
-	GoVersion   string       `json:"go_version"`
-	SandboxInfo *SandboxInfo `json:"sandbox"`
+	Version     string              `json:"version"`
+	BuildDate   string              `json:"builddate"`
+	GoVersion   string              `json:"go_version"`
+	SandboxInfo *SandboxInfo        `json:"sandbox"`
+	Pool        map[string]PoolInfo `json:"pool,omitempty"`
 }
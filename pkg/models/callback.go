@@ -0,0 +1,12 @@
+package models
+
+// CallbackPayload is posted to the callback URL
+// of an execution when the execution has finished.
+//
+// Result is set if the execution has been
+// performed. Otherwise, Error is set.
+type CallbackPayload struct {
+	RunId  string             `json:"runid,omitempty"`
+	Result *ExecutionResponse `json:"result,omitempty"`
+	Error  *ErrorModel        `json:"error,omitempty"`
+}
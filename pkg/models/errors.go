@@ -10,6 +10,7 @@ var (
 	ErrEmptyCode          = WsError{400, "code is empty"}
 	ErrSandboxNotRunning  = WsError{400, "sandbox is not running"}
 	ErrRateLimited        = WsError{429, "you have been rate limited"}
+	ErrLanguageNotAllowed = WsError{403, "language is not allowed"}
 )
 
 type WsError struct {
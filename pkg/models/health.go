@@ -0,0 +1,20 @@
+package models
+
+// HealthCheck is the result of a single
+// readiness check.
+//
+// Error is set when the check failed.
+type HealthCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Readiness is the response model of
+// the readiness endpoint.
+//
+// Ready is only set if all checks passed.
+type Readiness struct {
+	Ready  bool          `json:"ready"`
+	Checks []HealthCheck `json:"checks"`
+}
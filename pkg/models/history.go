@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Execution is the record of an execution
+// kept in the execution history.
+//
+// Response is set if the execution has been
+// performed. Otherwise, Error is set.
+type Execution struct {
+	ID       string             `json:"id"`
+	Language string             `json:"language"`
+	Client   string             `json:"client,omitempty"`
+	Created  time.Time          `json:"created"`
+	Request  *ExecutionRequest  `json:"request"`
+	Response *ExecutionResponse `json:"response,omitempty"`
+	Error    *ErrorModel        `json:"error,omitempty"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// JobStatus describes the state of
+// an asynchronous execution job.
+type JobStatus string
+
+const (
+	// JobPending is set when the job is waiting
+	// for its sandbox to be spawned.
+	JobPending JobStatus = "pending"
+	// JobRunning is set when the sandbox of
+	// the job is running.
+	JobRunning JobStatus = "running"
+	// JobFinished is set when the execution
+	// of the job has finished.
+	JobFinished JobStatus = "finished"
+	// JobFailed is set when the execution of
+	// the job could not be performed.
+	JobFailed JobStatus = "failed"
+	// JobCanceled is set when the job has
+	// been canceled.
+	JobCanceled JobStatus = "canceled"
+)
+
+// Job is the model of an asynchronous
+// execution job.
+//
+// Result is set when the execution has finished.
+// Error is set when the execution failed.
+type Job struct {
+	ID       string             `json:"id"`
+	Status   JobStatus          `json:"status"`
+	RunId    string             `json:"runid,omitempty"`
+	Created  time.Time          `json:"created"`
+	Finished *time.Time         `json:"finished,omitempty"`
+	Result   *ExecutionResponse `json:"result,omitempty"`
+	Error    *ErrorModel        `json:"error,omitempty"`
+}
+
+// IsDone returns true if the job will
+// not change its state anymore.
+func (t Job) IsDone() bool {
+	return t.Status == JobFinished || t.Status == JobFailed || t.Status == JobCanceled
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// SnippetRequest is the request model
+// to create a snippet.
+//
+// If ExpiresSeconds is larger than 0, the snippet
+// is deleted after the given number of seconds.
+type SnippetRequest struct {
+	Language       string            `json:"language"`
+	Code           string            `json:"code"`
+	Arguments      []string          `json:"arguments,omitempty"`
+	Environment    map[string]string `json:"environment,omitempty"`
+	ExpiresSeconds int               `json:"expires_seconds,omitempty"`
+}
+
+// Snippet is the model of a stored
+// code snippet.
+//
+// Expires is set if the snippet is
+// deleted after the given time.
+type Snippet struct {
+	ID          string            `json:"id"`
+	Language    string            `json:"language"`
+	Code        string            `json:"code"`
+	Arguments   []string          `json:"arguments,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Created     time.Time         `json:"created"`
+	Expires     *time.Time        `json:"expires,omitempty"`
+}
+
+// IsExpired returns true if the snippet
+// has expired at the given time.
+func (t Snippet) IsExpired(now time.Time) bool {
+	return t.Expires != nil && now.After(*t.Expires)
+}
+
+// ExecutionRequest returns the request
+// to execute the snippet.
+func (t Snippet) ExecutionRequest() *ExecutionRequest {
+	return &ExecutionRequest{
+		Language:    t.Language,
+		Code:        t.Code,
+		Arguments:   t.Arguments,
+		Environment: t.Environment,
+	}
+}
@@ -6,16 +6,79 @@ import (
 )
 
 // Spec defines a code environment specification.
+//
+// FileName defines the name of the file the code is
+// written to, which is the entry point of the execution.
+//
+// PoolSize defines the number of sandboxes which are
+// kept pre-created for the spec to reduce the startup
+// latency of executions.
+//
+// MaxConcurrent limits the number of executions of the
+// spec which are run concurrently. Further executions
+// are queued.
+//
+// If Build is specified, its command is executed in a
+// separate sandbox before the run phase, which executes
+// the command of Run or, if not specified, Cmd. The run
+// phase is skipped if the build fails.
+//
+// If Cacheable is set, executions of the spec are
+// considered deterministic and their results are
+// cached for identical requests.
 type Spec struct {
-	Image      string      `json:"image,omitempty" yaml:"image,omitempty"`
-	Entrypoint string      `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
-	FileName   string      `json:"filename,omitempty" yaml:"filename,omitempty"`
-	Cmd        string      `json:"cmd,omitempty" yaml:"cmd,omitempty"`
-	Registry   string      `json:"registry,omitempty" yaml:"registry,omitempty"`
-	Use        string      `json:"use,omitempty" yaml:"use,omitempty"`
-	Language   string      `json:"language,omitempty" yaml:"language,omitempty"`
-	Example    string      `json:"example,omitempty" yaml:"example,omitempty"`
-	Inline     *InlineSpec `json:"inline,omitempty" yaml:"inline,omitempty"`
+	Image         string         `json:"image,omitempty" yaml:"image,omitempty"`
+	Entrypoint    string         `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+	FileName      string         `json:"filename,omitempty" yaml:"filename,omitempty"`
+	Cmd           string         `json:"cmd,omitempty" yaml:"cmd,omitempty"`
+	Registry      string         `json:"registry,omitempty" yaml:"registry,omitempty"`
+	Use           string         `json:"use,omitempty" yaml:"use,omitempty"`
+	Language      string         `json:"language,omitempty" yaml:"language,omitempty"`
+	Example       string         `json:"example,omitempty" yaml:"example,omitempty"`
+	Inline        *InlineSpec    `json:"inline,omitempty" yaml:"inline,omitempty"`
+	Resources     *ResourceSpec  `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Hardening     *HardeningSpec `json:"hardening,omitempty" yaml:"hardening,omitempty"`
+	PoolSize      int            `json:"poolsize,omitempty" yaml:"poolsize,omitempty"`
+	MaxConcurrent int            `json:"maxconcurrent,omitempty" yaml:"maxconcurrent,omitempty"`
+	Build         *PhaseSpec     `json:"build,omitempty" yaml:"build,omitempty"`
+	Run           *PhaseSpec     `json:"run,omitempty" yaml:"run,omitempty"`
+	Cacheable     bool           `json:"cacheable,omitempty" yaml:"cacheable,omitempty"`
+}
+
+// PhaseSpec defines the command and the timeout
+// of the build or run phase of a spec.
+type PhaseSpec struct {
+	Cmd            string `json:"cmd,omitempty" yaml:"cmd,omitempty"`
+	TimeoutSeconds int    `json:"timeoutseconds,omitempty" yaml:"timeoutseconds,omitempty"`
+}
+
+// HardeningSpec defines opt-outs from the hardening
+// profile of the server for images which require
+// extra privileges.
+type HardeningSpec struct {
+	WritableRootFS     bool     `json:"writablerootfs,omitempty" yaml:"writablerootfs,omitempty"`
+	CapAdd             []string `json:"capadd,omitempty" yaml:"capadd,omitempty"`
+	AllowNewPrivileges bool     `json:"allownewprivileges,omitempty" yaml:"allownewprivileges,omitempty"`
+	DefaultSeccomp     bool     `json:"defaultseccomp,omitempty" yaml:"defaultseccomp,omitempty"`
+	RunAsImageUser     bool     `json:"runasimageuser,omitempty" yaml:"runasimageuser,omitempty"`
+}
+
+// ResourceSpec defines resource constraints of a
+// spec which override the defaults of the server.
+type ResourceSpec struct {
+	CPUs       float64     `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	CPUShares  int64       `json:"cpushares,omitempty" yaml:"cpushares,omitempty"`
+	CPUSetCPUs string      `json:"cpusetcpus,omitempty" yaml:"cpusetcpus,omitempty"`
+	PidsLimit  int64       `json:"pidslimit,omitempty" yaml:"pidslimit,omitempty"`
+	Ulimits    *UlimitSpec `json:"ulimits,omitempty" yaml:"ulimits,omitempty"`
+}
+
+// UlimitSpec defines ulimits applied to the
+// processes running in a sandbox.
+type UlimitSpec struct {
+	NoFile int64  `json:"nofile,omitempty" yaml:"nofile,omitempty"`
+	FSize  string `json:"fsize,omitempty" yaml:"fsize,omitempty"`
+	NProc  int64  `json:"nproc,omitempty" yaml:"nproc,omitempty"`
 }
 
 type InlineSpec struct {
@@ -8,6 +8,12 @@ const (
 	EventSpawn
 	EventLog
 	EventStop
+	EventBuildStart
+	EventBuildEnd
+	EventRunStart
+	EventArtifact
+	EventStats
+	EventQueued
 )
 
 type OpCode int
@@ -16,6 +22,7 @@ const (
 	OpPing OpCode = iota
 	OpExec
 	OpKill
+	OpStdin
 )
 
 type Event struct {
@@ -39,6 +46,11 @@ type OperationKill struct {
 	Args DataRunId `json:"args"`
 }
 
+type OperationStdin struct {
+	Operation
+	Args DataStdin `json:"args"`
+}
+
 type DataRunId struct {
 	RunId string `json:"runid"`
 }
@@ -49,9 +61,39 @@ type DataLog struct {
 	StdErr string `json:"stderr,omitempty"`
 }
 
+type DataStdin struct {
+	DataRunId
+	Stdin string `json:"stdin,omitempty"`
+	Close bool   `json:"close,omitempty"`
+}
+
 type DataStop struct {
 	DataRunId
-	ExecTimeMS int `json:"exectimems"`
+	ExecTimeMS         int               `json:"exectimems"`
+	ExitCode           int               `json:"exit_code"`
+	TerminationReason  TerminationReason `json:"termination_reason"`
+	ArtifactsTruncated bool              `json:"artifacts_truncated,omitempty"`
+	Usage              *ResourceUsage    `json:"usage,omitempty"`
+	Cached             bool              `json:"cached,omitempty"`
+}
+
+type DataBuildEnd struct {
+	DataRunId
+	BuildResponse
+}
+
+type DataArtifact struct {
+	DataRunId
+	Artifact
+}
+
+type DataQueued struct {
+	Position int `json:"position"`
+}
+
+type DataStats struct {
+	DataRunId
+	ResourceUsage
 }
 
 type DataError struct {
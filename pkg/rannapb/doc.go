@@ -0,0 +1,20 @@
+// Package rannapb contains the message types and
+// the client and server stubs of the ranna gRPC API
+// generated from proto/ranna/v1/ranna.proto.
+//
+// A client can be created by passing a connection
+// to NewRannaClient.
+//
+//	conn, err := grpc.NewClient("localhost:9090",
+//		grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	if err != nil {
+//		return err
+//	}
+//	client := rannapb.NewRannaClient(conn)
+//	res, err := client.Exec(ctx, &rannapb.ExecutionRequest{
+//		Language: "python3",
+//		Code:     "print('hello world')",
+//	})
+package rannapb
+
+//go:generate sh ../../scripts/protogen.sh
@@ -0,0 +1,2039 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: ranna/v1/ranna.proto
+
+package rannapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExecutionRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Language         string                 `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
+	Code             string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	InlineExpression bool                   `protobuf:"varint,3,opt,name=inline_expression,json=inlineExpression,proto3" json:"inline_expression,omitempty"`
+	Arguments        []string               `protobuf:"bytes,4,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	Environment      map[string]string      `protobuf:"bytes,5,rep,name=environment,proto3" json:"environment,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Files            map[string]string      `protobuf:"bytes,6,rep,name=files,proto3" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Stdin            string                 `protobuf:"bytes,7,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	Memory           string                 `protobuf:"bytes,8,opt,name=memory,proto3" json:"memory,omitempty"`
+	TimeoutSeconds   int32                  `protobuf:"varint,9,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	Cpus             float64                `protobuf:"fixed64,10,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	Artifacts        []string               `protobuf:"bytes,11,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	LiveStats        bool                   `protobuf:"varint,12,opt,name=live_stats,json=liveStats,proto3" json:"live_stats,omitempty"`
+	CallbackUrl      string                 `protobuf:"bytes,13,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ExecutionRequest) Reset() {
+	*x = ExecutionRequest{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionRequest) ProtoMessage() {}
+
+func (x *ExecutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionRequest.ProtoReflect.Descriptor instead.
+func (*ExecutionRequest) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExecutionRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ExecutionRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ExecutionRequest) GetInlineExpression() bool {
+	if x != nil {
+		return x.InlineExpression
+	}
+	return false
+}
+
+func (x *ExecutionRequest) GetArguments() []string {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+func (x *ExecutionRequest) GetEnvironment() map[string]string {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *ExecutionRequest) GetFiles() map[string]string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ExecutionRequest) GetStdin() string {
+	if x != nil {
+		return x.Stdin
+	}
+	return ""
+}
+
+func (x *ExecutionRequest) GetMemory() string {
+	if x != nil {
+		return x.Memory
+	}
+	return ""
+}
+
+func (x *ExecutionRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *ExecutionRequest) GetCpus() float64 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *ExecutionRequest) GetArtifacts() []string {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *ExecutionRequest) GetLiveStats() bool {
+	if x != nil {
+		return x.LiveStats
+	}
+	return false
+}
+
+func (x *ExecutionRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+type BuildResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Stdout            string                 `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr            string                 `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	ExitCode          int32                  `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	TimeMs            int64                  `protobuf:"varint,4,opt,name=time_ms,json=timeMs,proto3" json:"time_ms,omitempty"`
+	TerminationReason string                 `protobuf:"bytes,5,opt,name=termination_reason,json=terminationReason,proto3" json:"termination_reason,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *BuildResponse) Reset() {
+	*x = BuildResponse{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildResponse) ProtoMessage() {}
+
+func (x *BuildResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildResponse.ProtoReflect.Descriptor instead.
+func (*BuildResponse) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BuildResponse) GetStdout() string {
+	if x != nil {
+		return x.Stdout
+	}
+	return ""
+}
+
+func (x *BuildResponse) GetStderr() string {
+	if x != nil {
+		return x.Stderr
+	}
+	return ""
+}
+
+func (x *BuildResponse) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *BuildResponse) GetTimeMs() int64 {
+	if x != nil {
+		return x.TimeMs
+	}
+	return 0
+}
+
+func (x *BuildResponse) GetTerminationReason() string {
+	if x != nil {
+		return x.TerminationReason
+	}
+	return ""
+}
+
+type Artifact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MimeType      string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Artifact) Reset() {
+	*x = Artifact{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Artifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artifact) ProtoMessage() {}
+
+func (x *Artifact) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artifact.ProtoReflect.Descriptor instead.
+func (*Artifact) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Artifact) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Artifact) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *Artifact) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Artifact) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ResourceUsage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PeakMemoryBytes int64                  `protobuf:"varint,1,opt,name=peak_memory_bytes,json=peakMemoryBytes,proto3" json:"peak_memory_bytes,omitempty"`
+	CpuTimeMs       int64                  `protobuf:"varint,2,opt,name=cpu_time_ms,json=cpuTimeMs,proto3" json:"cpu_time_ms,omitempty"`
+	BlkioReadBytes  int64                  `protobuf:"varint,3,opt,name=blkio_read_bytes,json=blkioReadBytes,proto3" json:"blkio_read_bytes,omitempty"`
+	BlkioWriteBytes int64                  `protobuf:"varint,4,opt,name=blkio_write_bytes,json=blkioWriteBytes,proto3" json:"blkio_write_bytes,omitempty"`
+	PeakPids        int64                  `protobuf:"varint,5,opt,name=peak_pids,json=peakPids,proto3" json:"peak_pids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ResourceUsage) Reset() {
+	*x = ResourceUsage{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceUsage) ProtoMessage() {}
+
+func (x *ResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceUsage.ProtoReflect.Descriptor instead.
+func (*ResourceUsage) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResourceUsage) GetPeakMemoryBytes() int64 {
+	if x != nil {
+		return x.PeakMemoryBytes
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetCpuTimeMs() int64 {
+	if x != nil {
+		return x.CpuTimeMs
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetBlkioReadBytes() int64 {
+	if x != nil {
+		return x.BlkioReadBytes
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetBlkioWriteBytes() int64 {
+	if x != nil {
+		return x.BlkioWriteBytes
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetPeakPids() int64 {
+	if x != nil {
+		return x.PeakPids
+	}
+	return 0
+}
+
+type ExecutionResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Stdout             string                 `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr             string                 `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	ExecTimeMs         int64                  `protobuf:"varint,3,opt,name=exec_time_ms,json=execTimeMs,proto3" json:"exec_time_ms,omitempty"`
+	ExitCode           int32                  `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	TerminationReason  string                 `protobuf:"bytes,5,opt,name=termination_reason,json=terminationReason,proto3" json:"termination_reason,omitempty"`
+	Build              *BuildResponse         `protobuf:"bytes,6,opt,name=build,proto3" json:"build,omitempty"`
+	Artifacts          []*Artifact            `protobuf:"bytes,7,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	ArtifactsTruncated bool                   `protobuf:"varint,8,opt,name=artifacts_truncated,json=artifactsTruncated,proto3" json:"artifacts_truncated,omitempty"`
+	Usage              *ResourceUsage         `protobuf:"bytes,9,opt,name=usage,proto3" json:"usage,omitempty"`
+	Cached             bool                   `protobuf:"varint,10,opt,name=cached,proto3" json:"cached,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ExecutionResponse) Reset() {
+	*x = ExecutionResponse{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionResponse) ProtoMessage() {}
+
+func (x *ExecutionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionResponse.ProtoReflect.Descriptor instead.
+func (*ExecutionResponse) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExecutionResponse) GetStdout() string {
+	if x != nil {
+		return x.Stdout
+	}
+	return ""
+}
+
+func (x *ExecutionResponse) GetStderr() string {
+	if x != nil {
+		return x.Stderr
+	}
+	return ""
+}
+
+func (x *ExecutionResponse) GetExecTimeMs() int64 {
+	if x != nil {
+		return x.ExecTimeMs
+	}
+	return 0
+}
+
+func (x *ExecutionResponse) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *ExecutionResponse) GetTerminationReason() string {
+	if x != nil {
+		return x.TerminationReason
+	}
+	return ""
+}
+
+func (x *ExecutionResponse) GetBuild() *BuildResponse {
+	if x != nil {
+		return x.Build
+	}
+	return nil
+}
+
+func (x *ExecutionResponse) GetArtifacts() []*Artifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *ExecutionResponse) GetArtifactsTruncated() bool {
+	if x != nil {
+		return x.ArtifactsTruncated
+	}
+	return false
+}
+
+func (x *ExecutionResponse) GetUsage() *ResourceUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *ExecutionResponse) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	RunId string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_Queued_
+	//	*Event_Spawn_
+	//	*Event_Log_
+	//	*Event_BuildStart_
+	//	*Event_BuildEnd_
+	//	*Event_RunStart_
+	//	*Event_Artifact
+	//	*Event_Stats
+	//	*Event_Stop_
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Event) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetQueued() *Event_Queued {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Queued_); ok {
+			return x.Queued
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetSpawn() *Event_Spawn {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Spawn_); ok {
+			return x.Spawn
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetLog() *Event_Log {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Log_); ok {
+			return x.Log
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetBuildStart() *Event_BuildStart {
+	if x != nil {
+		if x, ok := x.Event.(*Event_BuildStart_); ok {
+			return x.BuildStart
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetBuildEnd() *Event_BuildEnd {
+	if x != nil {
+		if x, ok := x.Event.(*Event_BuildEnd_); ok {
+			return x.BuildEnd
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetRunStart() *Event_RunStart {
+	if x != nil {
+		if x, ok := x.Event.(*Event_RunStart_); ok {
+			return x.RunStart
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetArtifact() *Artifact {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Artifact); ok {
+			return x.Artifact
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetStats() *ResourceUsage {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Stats); ok {
+			return x.Stats
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetStop() *Event_Stop {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Stop_); ok {
+			return x.Stop
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_Queued_ struct {
+	Queued *Event_Queued `protobuf:"bytes,2,opt,name=queued,proto3,oneof"`
+}
+
+type Event_Spawn_ struct {
+	Spawn *Event_Spawn `protobuf:"bytes,3,opt,name=spawn,proto3,oneof"`
+}
+
+type Event_Log_ struct {
+	Log *Event_Log `protobuf:"bytes,4,opt,name=log,proto3,oneof"`
+}
+
+type Event_BuildStart_ struct {
+	BuildStart *Event_BuildStart `protobuf:"bytes,5,opt,name=build_start,json=buildStart,proto3,oneof"`
+}
+
+type Event_BuildEnd_ struct {
+	BuildEnd *Event_BuildEnd `protobuf:"bytes,6,opt,name=build_end,json=buildEnd,proto3,oneof"`
+}
+
+type Event_RunStart_ struct {
+	RunStart *Event_RunStart `protobuf:"bytes,7,opt,name=run_start,json=runStart,proto3,oneof"`
+}
+
+type Event_Artifact struct {
+	Artifact *Artifact `protobuf:"bytes,8,opt,name=artifact,proto3,oneof"`
+}
+
+type Event_Stats struct {
+	Stats *ResourceUsage `protobuf:"bytes,9,opt,name=stats,proto3,oneof"`
+}
+
+type Event_Stop_ struct {
+	Stop *Event_Stop `protobuf:"bytes,10,opt,name=stop,proto3,oneof"`
+}
+
+func (*Event_Queued_) isEvent_Event() {}
+
+func (*Event_Spawn_) isEvent_Event() {}
+
+func (*Event_Log_) isEvent_Event() {}
+
+func (*Event_BuildStart_) isEvent_Event() {}
+
+func (*Event_BuildEnd_) isEvent_Event() {}
+
+func (*Event_RunStart_) isEvent_Event() {}
+
+func (*Event_Artifact) isEvent_Event() {}
+
+func (*Event_Stats) isEvent_Event() {}
+
+func (*Event_Stop_) isEvent_Event() {}
+
+type InteractiveRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*InteractiveRequest_Exec
+	//	*InteractiveRequest_Stdin_
+	Request       isInteractiveRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InteractiveRequest) Reset() {
+	*x = InteractiveRequest{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InteractiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InteractiveRequest) ProtoMessage() {}
+
+func (x *InteractiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InteractiveRequest.ProtoReflect.Descriptor instead.
+func (*InteractiveRequest) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InteractiveRequest) GetRequest() isInteractiveRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *InteractiveRequest) GetExec() *ExecutionRequest {
+	if x != nil {
+		if x, ok := x.Request.(*InteractiveRequest_Exec); ok {
+			return x.Exec
+		}
+	}
+	return nil
+}
+
+func (x *InteractiveRequest) GetStdin() *InteractiveRequest_Stdin {
+	if x != nil {
+		if x, ok := x.Request.(*InteractiveRequest_Stdin_); ok {
+			return x.Stdin
+		}
+	}
+	return nil
+}
+
+type isInteractiveRequest_Request interface {
+	isInteractiveRequest_Request()
+}
+
+type InteractiveRequest_Exec struct {
+	// exec must be passed with the first message.
+	Exec *ExecutionRequest `protobuf:"bytes,1,opt,name=exec,proto3,oneof"`
+}
+
+type InteractiveRequest_Stdin_ struct {
+	Stdin *InteractiveRequest_Stdin `protobuf:"bytes,2,opt,name=stdin,proto3,oneof"`
+}
+
+func (*InteractiveRequest_Exec) isInteractiveRequest_Request() {}
+
+func (*InteractiveRequest_Stdin_) isInteractiveRequest_Request() {}
+
+type KillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KillRequest) Reset() {
+	*x = KillRequest{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillRequest) ProtoMessage() {}
+
+func (x *KillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillRequest.ProtoReflect.Descriptor instead.
+func (*KillRequest) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *KillRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type KillResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Killed        bool                   `protobuf:"varint,1,opt,name=killed,proto3" json:"killed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KillResponse) Reset() {
+	*x = KillResponse{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillResponse) ProtoMessage() {}
+
+func (x *KillResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillResponse.ProtoReflect.Descriptor instead.
+func (*KillResponse) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *KillResponse) GetKilled() bool {
+	if x != nil {
+		return x.Killed
+	}
+	return false
+}
+
+type GetSpecRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpecRequest) Reset() {
+	*x = GetSpecRequest{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpecRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpecRequest) ProtoMessage() {}
+
+func (x *GetSpecRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpecRequest.ProtoReflect.Descriptor instead.
+func (*GetSpecRequest) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{9}
+}
+
+type PhaseSpec struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Cmd            string                 `protobuf:"bytes,1,opt,name=cmd,proto3" json:"cmd,omitempty"`
+	TimeoutSeconds int32                  `protobuf:"varint,2,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PhaseSpec) Reset() {
+	*x = PhaseSpec{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PhaseSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhaseSpec) ProtoMessage() {}
+
+func (x *PhaseSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhaseSpec.ProtoReflect.Descriptor instead.
+func (*PhaseSpec) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PhaseSpec) GetCmd() string {
+	if x != nil {
+		return x.Cmd
+	}
+	return ""
+}
+
+func (x *PhaseSpec) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+type Spec struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Image          string                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Entrypoint     string                 `protobuf:"bytes,2,opt,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	Filename       string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	Cmd            string                 `protobuf:"bytes,4,opt,name=cmd,proto3" json:"cmd,omitempty"`
+	Registry       string                 `protobuf:"bytes,5,opt,name=registry,proto3" json:"registry,omitempty"`
+	Use            string                 `protobuf:"bytes,6,opt,name=use,proto3" json:"use,omitempty"`
+	Language       string                 `protobuf:"bytes,7,opt,name=language,proto3" json:"language,omitempty"`
+	Example        string                 `protobuf:"bytes,8,opt,name=example,proto3" json:"example,omitempty"`
+	SupportsInline bool                   `protobuf:"varint,9,opt,name=supports_inline,json=supportsInline,proto3" json:"supports_inline,omitempty"`
+	PoolSize       int32                  `protobuf:"varint,10,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+	MaxConcurrent  int32                  `protobuf:"varint,11,opt,name=max_concurrent,json=maxConcurrent,proto3" json:"max_concurrent,omitempty"`
+	Build          *PhaseSpec             `protobuf:"bytes,12,opt,name=build,proto3" json:"build,omitempty"`
+	Run            *PhaseSpec             `protobuf:"bytes,13,opt,name=run,proto3" json:"run,omitempty"`
+	Cacheable      bool                   `protobuf:"varint,14,opt,name=cacheable,proto3" json:"cacheable,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Spec) Reset() {
+	*x = Spec{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Spec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Spec) ProtoMessage() {}
+
+func (x *Spec) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Spec.ProtoReflect.Descriptor instead.
+func (*Spec) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Spec) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Spec) GetEntrypoint() string {
+	if x != nil {
+		return x.Entrypoint
+	}
+	return ""
+}
+
+func (x *Spec) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Spec) GetCmd() string {
+	if x != nil {
+		return x.Cmd
+	}
+	return ""
+}
+
+func (x *Spec) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+func (x *Spec) GetUse() string {
+	if x != nil {
+		return x.Use
+	}
+	return ""
+}
+
+func (x *Spec) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Spec) GetExample() string {
+	if x != nil {
+		return x.Example
+	}
+	return ""
+}
+
+func (x *Spec) GetSupportsInline() bool {
+	if x != nil {
+		return x.SupportsInline
+	}
+	return false
+}
+
+func (x *Spec) GetPoolSize() int32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+func (x *Spec) GetMaxConcurrent() int32 {
+	if x != nil {
+		return x.MaxConcurrent
+	}
+	return 0
+}
+
+func (x *Spec) GetBuild() *PhaseSpec {
+	if x != nil {
+		return x.Build
+	}
+	return nil
+}
+
+func (x *Spec) GetRun() *PhaseSpec {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+func (x *Spec) GetCacheable() bool {
+	if x != nil {
+		return x.Cacheable
+	}
+	return false
+}
+
+type GetSpecResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Specs         map[string]*Spec       `protobuf:"bytes,1,rep,name=specs,proto3" json:"specs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpecResponse) Reset() {
+	*x = GetSpecResponse{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpecResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpecResponse) ProtoMessage() {}
+
+func (x *GetSpecResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpecResponse.ProtoReflect.Descriptor instead.
+func (*GetSpecResponse) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSpecResponse) GetSpecs() map[string]*Spec {
+	if x != nil {
+		return x.Specs
+	}
+	return nil
+}
+
+type GetInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoRequest) ProtoMessage() {}
+
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{13}
+}
+
+type SandboxInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SandboxInfo) Reset() {
+	*x = SandboxInfo{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SandboxInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SandboxInfo) ProtoMessage() {}
+
+func (x *SandboxInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SandboxInfo.ProtoReflect.Descriptor instead.
+func (*SandboxInfo) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SandboxInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SandboxInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type PoolInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int32                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Available     int32                  `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolInfo) Reset() {
+	*x = PoolInfo{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolInfo) ProtoMessage() {}
+
+func (x *PoolInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolInfo.ProtoReflect.Descriptor instead.
+func (*PoolInfo) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PoolInfo) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PoolInfo) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+type GetInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	BuildDate     string                 `protobuf:"bytes,2,opt,name=build_date,json=buildDate,proto3" json:"build_date,omitempty"`
+	GoVersion     string                 `protobuf:"bytes,3,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	Sandbox       *SandboxInfo           `protobuf:"bytes,4,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+	Pool          map[string]*PoolInfo   `protobuf:"bytes,5,rep,name=pool,proto3" json:"pool,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoResponse) ProtoMessage() {}
+
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetInfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetBuildDate() string {
+	if x != nil {
+		return x.BuildDate
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetSandbox() *SandboxInfo {
+	if x != nil {
+		return x.Sandbox
+	}
+	return nil
+}
+
+func (x *GetInfoResponse) GetPool() map[string]*PoolInfo {
+	if x != nil {
+		return x.Pool
+	}
+	return nil
+}
+
+type Event_Queued struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      int32                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event_Queued) Reset() {
+	*x = Event_Queued{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event_Queued) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_Queued) ProtoMessage() {}
+
+func (x *Event_Queued) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_Queued.ProtoReflect.Descriptor instead.
+func (*Event_Queued) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *Event_Queued) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type Event_Spawn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event_Spawn) Reset() {
+	*x = Event_Spawn{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event_Spawn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_Spawn) ProtoMessage() {}
+
+func (x *Event_Spawn) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_Spawn.ProtoReflect.Descriptor instead.
+func (*Event_Spawn) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5, 1}
+}
+
+type Event_Log struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stdout        []byte                 `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr        []byte                 `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event_Log) Reset() {
+	*x = Event_Log{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event_Log) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_Log) ProtoMessage() {}
+
+func (x *Event_Log) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_Log.ProtoReflect.Descriptor instead.
+func (*Event_Log) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5, 2}
+}
+
+func (x *Event_Log) GetStdout() []byte {
+	if x != nil {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (x *Event_Log) GetStderr() []byte {
+	if x != nil {
+		return x.Stderr
+	}
+	return nil
+}
+
+type Event_BuildStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event_BuildStart) Reset() {
+	*x = Event_BuildStart{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event_BuildStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_BuildStart) ProtoMessage() {}
+
+func (x *Event_BuildStart) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_BuildStart.ProtoReflect.Descriptor instead.
+func (*Event_BuildStart) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5, 3}
+}
+
+type Event_BuildEnd struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Build         *BuildResponse         `protobuf:"bytes,1,opt,name=build,proto3" json:"build,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event_BuildEnd) Reset() {
+	*x = Event_BuildEnd{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event_BuildEnd) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_BuildEnd) ProtoMessage() {}
+
+func (x *Event_BuildEnd) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_BuildEnd.ProtoReflect.Descriptor instead.
+func (*Event_BuildEnd) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5, 4}
+}
+
+func (x *Event_BuildEnd) GetBuild() *BuildResponse {
+	if x != nil {
+		return x.Build
+	}
+	return nil
+}
+
+type Event_RunStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event_RunStart) Reset() {
+	*x = Event_RunStart{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event_RunStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_RunStart) ProtoMessage() {}
+
+func (x *Event_RunStart) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_RunStart.ProtoReflect.Descriptor instead.
+func (*Event_RunStart) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5, 5}
+}
+
+type Event_Stop struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ExecTimeMs         int64                  `protobuf:"varint,1,opt,name=exec_time_ms,json=execTimeMs,proto3" json:"exec_time_ms,omitempty"`
+	ExitCode           int32                  `protobuf:"varint,2,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	TerminationReason  string                 `protobuf:"bytes,3,opt,name=termination_reason,json=terminationReason,proto3" json:"termination_reason,omitempty"`
+	ArtifactsTruncated bool                   `protobuf:"varint,4,opt,name=artifacts_truncated,json=artifactsTruncated,proto3" json:"artifacts_truncated,omitempty"`
+	Usage              *ResourceUsage         `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	Cached             bool                   `protobuf:"varint,6,opt,name=cached,proto3" json:"cached,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Event_Stop) Reset() {
+	*x = Event_Stop{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event_Stop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_Stop) ProtoMessage() {}
+
+func (x *Event_Stop) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_Stop.ProtoReflect.Descriptor instead.
+func (*Event_Stop) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{5, 6}
+}
+
+func (x *Event_Stop) GetExecTimeMs() int64 {
+	if x != nil {
+		return x.ExecTimeMs
+	}
+	return 0
+}
+
+func (x *Event_Stop) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *Event_Stop) GetTerminationReason() string {
+	if x != nil {
+		return x.TerminationReason
+	}
+	return ""
+}
+
+func (x *Event_Stop) GetArtifactsTruncated() bool {
+	if x != nil {
+		return x.ArtifactsTruncated
+	}
+	return false
+}
+
+func (x *Event_Stop) GetUsage() *ResourceUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *Event_Stop) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+type InteractiveRequest_Stdin struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Data  []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// close closes the stdin stream after data
+	// has been written.
+	Close         bool `protobuf:"varint,2,opt,name=close,proto3" json:"close,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InteractiveRequest_Stdin) Reset() {
+	*x = InteractiveRequest_Stdin{}
+	mi := &file_ranna_v1_ranna_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InteractiveRequest_Stdin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InteractiveRequest_Stdin) ProtoMessage() {}
+
+func (x *InteractiveRequest_Stdin) ProtoReflect() protoreflect.Message {
+	mi := &file_ranna_v1_ranna_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InteractiveRequest_Stdin.ProtoReflect.Descriptor instead.
+func (*InteractiveRequest_Stdin) Descriptor() ([]byte, []int) {
+	return file_ranna_v1_ranna_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (x *InteractiveRequest_Stdin) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *InteractiveRequest_Stdin) GetClose() bool {
+	if x != nil {
+		return x.Close
+	}
+	return false
+}
+
+var File_ranna_v1_ranna_proto protoreflect.FileDescriptor
+
+const file_ranna_v1_ranna_proto_rawDesc = "" +
+	"\n" +
+	"\x14ranna/v1/ranna.proto\x12\branna.v1\"\xde\x04\n" +
+	"\x10ExecutionRequest\x12\x1a\n" +
+	"\blanguage\x18\x01 \x01(\tR\blanguage\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12+\n" +
+	"\x11inline_expression\x18\x03 \x01(\bR\x10inlineExpression\x12\x1c\n" +
+	"\targuments\x18\x04 \x03(\tR\targuments\x12M\n" +
+	"\venvironment\x18\x05 \x03(\v2+.ranna.v1.ExecutionRequest.EnvironmentEntryR\venvironment\x12;\n" +
+	"\x05files\x18\x06 \x03(\v2%.ranna.v1.ExecutionRequest.FilesEntryR\x05files\x12\x14\n" +
+	"\x05stdin\x18\a \x01(\tR\x05stdin\x12\x16\n" +
+	"\x06memory\x18\b \x01(\tR\x06memory\x12'\n" +
+	"\x0ftimeout_seconds\x18\t \x01(\x05R\x0etimeoutSeconds\x12\x12\n" +
+	"\x04cpus\x18\n" +
+	" \x01(\x01R\x04cpus\x12\x1c\n" +
+	"\tartifacts\x18\v \x03(\tR\tartifacts\x12\x1d\n" +
+	"\n" +
+	"live_stats\x18\f \x01(\bR\tliveStats\x12!\n" +
+	"\fcallback_url\x18\r \x01(\tR\vcallbackUrl\x1a>\n" +
+	"\x10EnvironmentEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"FilesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa4\x01\n" +
+	"\rBuildResponse\x12\x16\n" +
+	"\x06stdout\x18\x01 \x01(\tR\x06stdout\x12\x16\n" +
+	"\x06stderr\x18\x02 \x01(\tR\x06stderr\x12\x1b\n" +
+	"\texit_code\x18\x03 \x01(\x05R\bexitCode\x12\x17\n" +
+	"\atime_ms\x18\x04 \x01(\x03R\x06timeMs\x12-\n" +
+	"\x12termination_reason\x18\x05 \x01(\tR\x11terminationReason\"c\n" +
+	"\bArtifact\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\"\xce\x01\n" +
+	"\rResourceUsage\x12*\n" +
+	"\x11peak_memory_bytes\x18\x01 \x01(\x03R\x0fpeakMemoryBytes\x12\x1e\n" +
+	"\vcpu_time_ms\x18\x02 \x01(\x03R\tcpuTimeMs\x12(\n" +
+	"\x10blkio_read_bytes\x18\x03 \x01(\x03R\x0eblkioReadBytes\x12*\n" +
+	"\x11blkio_write_bytes\x18\x04 \x01(\x03R\x0fblkioWriteBytes\x12\x1b\n" +
+	"\tpeak_pids\x18\x05 \x01(\x03R\bpeakPids\"\x8a\x03\n" +
+	"\x11ExecutionResponse\x12\x16\n" +
+	"\x06stdout\x18\x01 \x01(\tR\x06stdout\x12\x16\n" +
+	"\x06stderr\x18\x02 \x01(\tR\x06stderr\x12 \n" +
+	"\fexec_time_ms\x18\x03 \x01(\x03R\n" +
+	"execTimeMs\x12\x1b\n" +
+	"\texit_code\x18\x04 \x01(\x05R\bexitCode\x12-\n" +
+	"\x12termination_reason\x18\x05 \x01(\tR\x11terminationReason\x12-\n" +
+	"\x05build\x18\x06 \x01(\v2\x17.ranna.v1.BuildResponseR\x05build\x120\n" +
+	"\tartifacts\x18\a \x03(\v2\x12.ranna.v1.ArtifactR\tartifacts\x12/\n" +
+	"\x13artifacts_truncated\x18\b \x01(\bR\x12artifactsTruncated\x12-\n" +
+	"\x05usage\x18\t \x01(\v2\x17.ranna.v1.ResourceUsageR\x05usage\x12\x16\n" +
+	"\x06cached\x18\n" +
+	" \x01(\bR\x06cached\"\x9b\a\n" +
+	"\x05Event\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x120\n" +
+	"\x06queued\x18\x02 \x01(\v2\x16.ranna.v1.Event.QueuedH\x00R\x06queued\x12-\n" +
+	"\x05spawn\x18\x03 \x01(\v2\x15.ranna.v1.Event.SpawnH\x00R\x05spawn\x12'\n" +
+	"\x03log\x18\x04 \x01(\v2\x13.ranna.v1.Event.LogH\x00R\x03log\x12=\n" +
+	"\vbuild_start\x18\x05 \x01(\v2\x1a.ranna.v1.Event.BuildStartH\x00R\n" +
+	"buildStart\x127\n" +
+	"\tbuild_end\x18\x06 \x01(\v2\x18.ranna.v1.Event.BuildEndH\x00R\bbuildEnd\x127\n" +
+	"\trun_start\x18\a \x01(\v2\x18.ranna.v1.Event.RunStartH\x00R\brunStart\x120\n" +
+	"\bartifact\x18\b \x01(\v2\x12.ranna.v1.ArtifactH\x00R\bartifact\x12/\n" +
+	"\x05stats\x18\t \x01(\v2\x17.ranna.v1.ResourceUsageH\x00R\x05stats\x12*\n" +
+	"\x04stop\x18\n" +
+	" \x01(\v2\x14.ranna.v1.Event.StopH\x00R\x04stop\x1a$\n" +
+	"\x06Queued\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x05R\bposition\x1a\a\n" +
+	"\x05Spawn\x1a5\n" +
+	"\x03Log\x12\x16\n" +
+	"\x06stdout\x18\x01 \x01(\fR\x06stdout\x12\x16\n" +
+	"\x06stderr\x18\x02 \x01(\fR\x06stderr\x1a\f\n" +
+	"\n" +
+	"BuildStart\x1a9\n" +
+	"\bBuildEnd\x12-\n" +
+	"\x05build\x18\x01 \x01(\v2\x17.ranna.v1.BuildResponseR\x05build\x1a\n" +
+	"\n" +
+	"\bRunStart\x1a\xec\x01\n" +
+	"\x04Stop\x12 \n" +
+	"\fexec_time_ms\x18\x01 \x01(\x03R\n" +
+	"execTimeMs\x12\x1b\n" +
+	"\texit_code\x18\x02 \x01(\x05R\bexitCode\x12-\n" +
+	"\x12termination_reason\x18\x03 \x01(\tR\x11terminationReason\x12/\n" +
+	"\x13artifacts_truncated\x18\x04 \x01(\bR\x12artifactsTruncated\x12-\n" +
+	"\x05usage\x18\x05 \x01(\v2\x17.ranna.v1.ResourceUsageR\x05usage\x12\x16\n" +
+	"\x06cached\x18\x06 \x01(\bR\x06cachedB\a\n" +
+	"\x05event\"\xc0\x01\n" +
+	"\x12InteractiveRequest\x120\n" +
+	"\x04exec\x18\x01 \x01(\v2\x1a.ranna.v1.ExecutionRequestH\x00R\x04exec\x12:\n" +
+	"\x05stdin\x18\x02 \x01(\v2\".ranna.v1.InteractiveRequest.StdinH\x00R\x05stdin\x1a1\n" +
+	"\x05Stdin\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x14\n" +
+	"\x05close\x18\x02 \x01(\bR\x05closeB\t\n" +
+	"\arequest\"$\n" +
+	"\vKillRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"&\n" +
+	"\fKillResponse\x12\x16\n" +
+	"\x06killed\x18\x01 \x01(\bR\x06killed\"\x10\n" +
+	"\x0eGetSpecRequest\"F\n" +
+	"\tPhaseSpec\x12\x10\n" +
+	"\x03cmd\x18\x01 \x01(\tR\x03cmd\x12'\n" +
+	"\x0ftimeout_seconds\x18\x02 \x01(\x05R\x0etimeoutSeconds\"\xab\x03\n" +
+	"\x04Spec\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\tR\x05image\x12\x1e\n" +
+	"\n" +
+	"entrypoint\x18\x02 \x01(\tR\n" +
+	"entrypoint\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12\x10\n" +
+	"\x03cmd\x18\x04 \x01(\tR\x03cmd\x12\x1a\n" +
+	"\bregistry\x18\x05 \x01(\tR\bregistry\x12\x10\n" +
+	"\x03use\x18\x06 \x01(\tR\x03use\x12\x1a\n" +
+	"\blanguage\x18\a \x01(\tR\blanguage\x12\x18\n" +
+	"\aexample\x18\b \x01(\tR\aexample\x12'\n" +
+	"\x0fsupports_inline\x18\t \x01(\bR\x0esupportsInline\x12\x1b\n" +
+	"\tpool_size\x18\n" +
+	" \x01(\x05R\bpoolSize\x12%\n" +
+	"\x0emax_concurrent\x18\v \x01(\x05R\rmaxConcurrent\x12)\n" +
+	"\x05build\x18\f \x01(\v2\x13.ranna.v1.PhaseSpecR\x05build\x12%\n" +
+	"\x03run\x18\r \x01(\v2\x13.ranna.v1.PhaseSpecR\x03run\x12\x1c\n" +
+	"\tcacheable\x18\x0e \x01(\bR\tcacheable\"\x97\x01\n" +
+	"\x0fGetSpecResponse\x12:\n" +
+	"\x05specs\x18\x01 \x03(\v2$.ranna.v1.GetSpecResponse.SpecsEntryR\x05specs\x1aH\n" +
+	"\n" +
+	"SpecsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12$\n" +
+	"\x05value\x18\x02 \x01(\v2\x0e.ranna.v1.SpecR\x05value:\x028\x01\"\x10\n" +
+	"\x0eGetInfoRequest\";\n" +
+	"\vSandboxInfo\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"<\n" +
+	"\bPoolInfo\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x05R\x04size\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\x05R\tavailable\"\xa0\x02\n" +
+	"\x0fGetInfoResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1d\n" +
+	"\n" +
+	"build_date\x18\x02 \x01(\tR\tbuildDate\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x03 \x01(\tR\tgoVersion\x12/\n" +
+	"\asandbox\x18\x04 \x01(\v2\x15.ranna.v1.SandboxInfoR\asandbox\x127\n" +
+	"\x04pool\x18\x05 \x03(\v2#.ranna.v1.GetInfoResponse.PoolEntryR\x04pool\x1aK\n" +
+	"\tPoolEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.ranna.v1.PoolInfoR\x05value:\x028\x012\xfe\x02\n" +
+	"\x05Ranna\x12?\n" +
+	"\x04Exec\x12\x1a.ranna.v1.ExecutionRequest\x1a\x1b.ranna.v1.ExecutionResponse\x12;\n" +
+	"\n" +
+	"ExecStream\x12\x1a.ranna.v1.ExecutionRequest\x1a\x0f.ranna.v1.Event0\x01\x12@\n" +
+	"\vInteractive\x12\x1c.ranna.v1.InteractiveRequest\x1a\x0f.ranna.v1.Event(\x010\x01\x125\n" +
+	"\x04Kill\x12\x15.ranna.v1.KillRequest\x1a\x16.ranna.v1.KillResponse\x12>\n" +
+	"\aGetSpec\x12\x18.ranna.v1.GetSpecRequest\x1a\x19.ranna.v1.GetSpecResponse\x12>\n" +
+	"\aGetInfo\x12\x18.ranna.v1.GetInfoRequest\x1a\x19.ranna.v1.GetInfoResponseB'Z%github.com/ranna-go/ranna/pkg/rannapbb\x06proto3"
+
+var (
+	file_ranna_v1_ranna_proto_rawDescOnce sync.Once
+	file_ranna_v1_ranna_proto_rawDescData []byte
+)
+
+func file_ranna_v1_ranna_proto_rawDescGZIP() []byte {
+	file_ranna_v1_ranna_proto_rawDescOnce.Do(func() {
+		file_ranna_v1_ranna_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ranna_v1_ranna_proto_rawDesc), len(file_ranna_v1_ranna_proto_rawDesc)))
+	})
+	return file_ranna_v1_ranna_proto_rawDescData
+}
+
+var file_ranna_v1_ranna_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_ranna_v1_ranna_proto_goTypes = []any{
+	(*ExecutionRequest)(nil),         // 0: ranna.v1.ExecutionRequest
+	(*BuildResponse)(nil),            // 1: ranna.v1.BuildResponse
+	(*Artifact)(nil),                 // 2: ranna.v1.Artifact
+	(*ResourceUsage)(nil),            // 3: ranna.v1.ResourceUsage
+	(*ExecutionResponse)(nil),        // 4: ranna.v1.ExecutionResponse
+	(*Event)(nil),                    // 5: ranna.v1.Event
+	(*InteractiveRequest)(nil),       // 6: ranna.v1.InteractiveRequest
+	(*KillRequest)(nil),              // 7: ranna.v1.KillRequest
+	(*KillResponse)(nil),             // 8: ranna.v1.KillResponse
+	(*GetSpecRequest)(nil),           // 9: ranna.v1.GetSpecRequest
+	(*PhaseSpec)(nil),                // 10: ranna.v1.PhaseSpec
+	(*Spec)(nil),                     // 11: ranna.v1.Spec
+	(*GetSpecResponse)(nil),          // 12: ranna.v1.GetSpecResponse
+	(*GetInfoRequest)(nil),           // 13: ranna.v1.GetInfoRequest
+	(*SandboxInfo)(nil),              // 14: ranna.v1.SandboxInfo
+	(*PoolInfo)(nil),                 // 15: ranna.v1.PoolInfo
+	(*GetInfoResponse)(nil),          // 16: ranna.v1.GetInfoResponse
+	nil,                              // 17: ranna.v1.ExecutionRequest.EnvironmentEntry
+	nil,                              // 18: ranna.v1.ExecutionRequest.FilesEntry
+	(*Event_Queued)(nil),             // 19: ranna.v1.Event.Queued
+	(*Event_Spawn)(nil),              // 20: ranna.v1.Event.Spawn
+	(*Event_Log)(nil),                // 21: ranna.v1.Event.Log
+	(*Event_BuildStart)(nil),         // 22: ranna.v1.Event.BuildStart
+	(*Event_BuildEnd)(nil),           // 23: ranna.v1.Event.BuildEnd
+	(*Event_RunStart)(nil),           // 24: ranna.v1.Event.RunStart
+	(*Event_Stop)(nil),               // 25: ranna.v1.Event.Stop
+	(*InteractiveRequest_Stdin)(nil), // 26: ranna.v1.InteractiveRequest.Stdin
+	nil,                              // 27: ranna.v1.GetSpecResponse.SpecsEntry
+	nil,                              // 28: ranna.v1.GetInfoResponse.PoolEntry
+}
+var file_ranna_v1_ranna_proto_depIdxs = []int32{
+	17, // 0: ranna.v1.ExecutionRequest.environment:type_name -> ranna.v1.ExecutionRequest.EnvironmentEntry
+	18, // 1: ranna.v1.ExecutionRequest.files:type_name -> ranna.v1.ExecutionRequest.FilesEntry
+	1,  // 2: ranna.v1.ExecutionResponse.build:type_name -> ranna.v1.BuildResponse
+	2,  // 3: ranna.v1.ExecutionResponse.artifacts:type_name -> ranna.v1.Artifact
+	3,  // 4: ranna.v1.ExecutionResponse.usage:type_name -> ranna.v1.ResourceUsage
+	19, // 5: ranna.v1.Event.queued:type_name -> ranna.v1.Event.Queued
+	20, // 6: ranna.v1.Event.spawn:type_name -> ranna.v1.Event.Spawn
+	21, // 7: ranna.v1.Event.log:type_name -> ranna.v1.Event.Log
+	22, // 8: ranna.v1.Event.build_start:type_name -> ranna.v1.Event.BuildStart
+	23, // 9: ranna.v1.Event.build_end:type_name -> ranna.v1.Event.BuildEnd
+	24, // 10: ranna.v1.Event.run_start:type_name -> ranna.v1.Event.RunStart
+	2,  // 11: ranna.v1.Event.artifact:type_name -> ranna.v1.Artifact
+	3,  // 12: ranna.v1.Event.stats:type_name -> ranna.v1.ResourceUsage
+	25, // 13: ranna.v1.Event.stop:type_name -> ranna.v1.Event.Stop
+	0,  // 14: ranna.v1.InteractiveRequest.exec:type_name -> ranna.v1.ExecutionRequest
+	26, // 15: ranna.v1.InteractiveRequest.stdin:type_name -> ranna.v1.InteractiveRequest.Stdin
+	10, // 16: ranna.v1.Spec.build:type_name -> ranna.v1.PhaseSpec
+	10, // 17: ranna.v1.Spec.run:type_name -> ranna.v1.PhaseSpec
+	27, // 18: ranna.v1.GetSpecResponse.specs:type_name -> ranna.v1.GetSpecResponse.SpecsEntry
+	14, // 19: ranna.v1.GetInfoResponse.sandbox:type_name -> ranna.v1.SandboxInfo
+	28, // 20: ranna.v1.GetInfoResponse.pool:type_name -> ranna.v1.GetInfoResponse.PoolEntry
+	1,  // 21: ranna.v1.Event.BuildEnd.build:type_name -> ranna.v1.BuildResponse
+	3,  // 22: ranna.v1.Event.Stop.usage:type_name -> ranna.v1.ResourceUsage
+	11, // 23: ranna.v1.GetSpecResponse.SpecsEntry.value:type_name -> ranna.v1.Spec
+	15, // 24: ranna.v1.GetInfoResponse.PoolEntry.value:type_name -> ranna.v1.PoolInfo
+	0,  // 25: ranna.v1.Ranna.Exec:input_type -> ranna.v1.ExecutionRequest
+	0,  // 26: ranna.v1.Ranna.ExecStream:input_type -> ranna.v1.ExecutionRequest
+	6,  // 27: ranna.v1.Ranna.Interactive:input_type -> ranna.v1.InteractiveRequest
+	7,  // 28: ranna.v1.Ranna.Kill:input_type -> ranna.v1.KillRequest
+	9,  // 29: ranna.v1.Ranna.GetSpec:input_type -> ranna.v1.GetSpecRequest
+	13, // 30: ranna.v1.Ranna.GetInfo:input_type -> ranna.v1.GetInfoRequest
+	4,  // 31: ranna.v1.Ranna.Exec:output_type -> ranna.v1.ExecutionResponse
+	5,  // 32: ranna.v1.Ranna.ExecStream:output_type -> ranna.v1.Event
+	5,  // 33: ranna.v1.Ranna.Interactive:output_type -> ranna.v1.Event
+	8,  // 34: ranna.v1.Ranna.Kill:output_type -> ranna.v1.KillResponse
+	12, // 35: ranna.v1.Ranna.GetSpec:output_type -> ranna.v1.GetSpecResponse
+	16, // 36: ranna.v1.Ranna.GetInfo:output_type -> ranna.v1.GetInfoResponse
+	31, // [31:37] is the sub-list for method output_type
+	25, // [25:31] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_ranna_v1_ranna_proto_init() }
+func file_ranna_v1_ranna_proto_init() {
+	if File_ranna_v1_ranna_proto != nil {
+		return
+	}
+	file_ranna_v1_ranna_proto_msgTypes[5].OneofWrappers = []any{
+		(*Event_Queued_)(nil),
+		(*Event_Spawn_)(nil),
+		(*Event_Log_)(nil),
+		(*Event_BuildStart_)(nil),
+		(*Event_BuildEnd_)(nil),
+		(*Event_RunStart_)(nil),
+		(*Event_Artifact)(nil),
+		(*Event_Stats)(nil),
+		(*Event_Stop_)(nil),
+	}
+	file_ranna_v1_ranna_proto_msgTypes[6].OneofWrappers = []any{
+		(*InteractiveRequest_Exec)(nil),
+		(*InteractiveRequest_Stdin_)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ranna_v1_ranna_proto_rawDesc), len(file_ranna_v1_ranna_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ranna_v1_ranna_proto_goTypes,
+		DependencyIndexes: file_ranna_v1_ranna_proto_depIdxs,
+		MessageInfos:      file_ranna_v1_ranna_proto_msgTypes,
+	}.Build()
+	File_ranna_v1_ranna_proto = out.File
+	file_ranna_v1_ranna_proto_goTypes = nil
+	file_ranna_v1_ranna_proto_depIdxs = nil
+}
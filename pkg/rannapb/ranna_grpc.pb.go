@@ -0,0 +1,337 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: ranna/v1/ranna.proto
+
+package rannapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Ranna_Exec_FullMethodName        = "/ranna.v1.Ranna/Exec"
+	Ranna_ExecStream_FullMethodName  = "/ranna.v1.Ranna/ExecStream"
+	Ranna_Interactive_FullMethodName = "/ranna.v1.Ranna/Interactive"
+	Ranna_Kill_FullMethodName        = "/ranna.v1.Ranna/Kill"
+	Ranna_GetSpec_FullMethodName     = "/ranna.v1.Ranna/GetSpec"
+	Ranna_GetInfo_FullMethodName     = "/ranna.v1.Ranna/GetInfo"
+)
+
+// RannaClient is the client API for Ranna service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Ranna executes code in sandboxes.
+type RannaClient interface {
+	// Exec executes the code and returns the result
+	// after the execution has finished.
+	Exec(ctx context.Context, in *ExecutionRequest, opts ...grpc.CallOption) (*ExecutionResponse, error)
+	// ExecStream executes the code and streams the
+	// events of the execution. The last event is
+	// always a stop event.
+	ExecStream(ctx context.Context, in *ExecutionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// Interactive executes the code passed with the first
+	// message and streams the events of the execution.
+	// Further messages pass input into the stdin stream
+	// of the executed program.
+	Interactive(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[InteractiveRequest, Event], error)
+	// Kill kills a running execution.
+	Kill(ctx context.Context, in *KillRequest, opts ...grpc.CallOption) (*KillResponse, error)
+	// GetSpec returns the available spec map.
+	GetSpec(ctx context.Context, in *GetSpecRequest, opts ...grpc.CallOption) (*GetSpecResponse, error)
+	// GetInfo returns general system and version information.
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+}
+
+type rannaClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRannaClient(cc grpc.ClientConnInterface) RannaClient {
+	return &rannaClient{cc}
+}
+
+func (c *rannaClient) Exec(ctx context.Context, in *ExecutionRequest, opts ...grpc.CallOption) (*ExecutionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecutionResponse)
+	err := c.cc.Invoke(ctx, Ranna_Exec_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rannaClient) ExecStream(ctx context.Context, in *ExecutionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Ranna_ServiceDesc.Streams[0], Ranna_ExecStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecutionRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ranna_ExecStreamClient = grpc.ServerStreamingClient[Event]
+
+func (c *rannaClient) Interactive(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[InteractiveRequest, Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Ranna_ServiceDesc.Streams[1], Ranna_Interactive_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[InteractiveRequest, Event]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ranna_InteractiveClient = grpc.BidiStreamingClient[InteractiveRequest, Event]
+
+func (c *rannaClient) Kill(ctx context.Context, in *KillRequest, opts ...grpc.CallOption) (*KillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KillResponse)
+	err := c.cc.Invoke(ctx, Ranna_Kill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rannaClient) GetSpec(ctx context.Context, in *GetSpecRequest, opts ...grpc.CallOption) (*GetSpecResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSpecResponse)
+	err := c.cc.Invoke(ctx, Ranna_GetSpec_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rannaClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInfoResponse)
+	err := c.cc.Invoke(ctx, Ranna_GetInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RannaServer is the server API for Ranna service.
+// All implementations must embed UnimplementedRannaServer
+// for forward compatibility.
+//
+// Ranna executes code in sandboxes.
+type RannaServer interface {
+	// Exec executes the code and returns the result
+	// after the execution has finished.
+	Exec(context.Context, *ExecutionRequest) (*ExecutionResponse, error)
+	// ExecStream executes the code and streams the
+	// events of the execution. The last event is
+	// always a stop event.
+	ExecStream(*ExecutionRequest, grpc.ServerStreamingServer[Event]) error
+	// Interactive executes the code passed with the first
+	// message and streams the events of the execution.
+	// Further messages pass input into the stdin stream
+	// of the executed program.
+	Interactive(grpc.BidiStreamingServer[InteractiveRequest, Event]) error
+	// Kill kills a running execution.
+	Kill(context.Context, *KillRequest) (*KillResponse, error)
+	// GetSpec returns the available spec map.
+	GetSpec(context.Context, *GetSpecRequest) (*GetSpecResponse, error)
+	// GetInfo returns general system and version information.
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	mustEmbedUnimplementedRannaServer()
+}
+
+// UnimplementedRannaServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRannaServer struct{}
+
+func (UnimplementedRannaServer) Exec(context.Context, *ExecutionRequest) (*ExecutionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
+func (UnimplementedRannaServer) ExecStream(*ExecutionRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method ExecStream not implemented")
+}
+func (UnimplementedRannaServer) Interactive(grpc.BidiStreamingServer[InteractiveRequest, Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Interactive not implemented")
+}
+func (UnimplementedRannaServer) Kill(context.Context, *KillRequest) (*KillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Kill not implemented")
+}
+func (UnimplementedRannaServer) GetSpec(context.Context, *GetSpecRequest) (*GetSpecResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpec not implemented")
+}
+func (UnimplementedRannaServer) GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (UnimplementedRannaServer) mustEmbedUnimplementedRannaServer() {}
+func (UnimplementedRannaServer) testEmbeddedByValue()               {}
+
+// UnsafeRannaServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RannaServer will
+// result in compilation errors.
+type UnsafeRannaServer interface {
+	mustEmbedUnimplementedRannaServer()
+}
+
+func RegisterRannaServer(s grpc.ServiceRegistrar, srv RannaServer) {
+	// If the following call pancis, it indicates UnimplementedRannaServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Ranna_ServiceDesc, srv)
+}
+
+func _Ranna_Exec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RannaServer).Exec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ranna_Exec_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RannaServer).Exec(ctx, req.(*ExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ranna_ExecStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecutionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RannaServer).ExecStream(m, &grpc.GenericServerStream[ExecutionRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ranna_ExecStreamServer = grpc.ServerStreamingServer[Event]
+
+func _Ranna_Interactive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RannaServer).Interactive(&grpc.GenericServerStream[InteractiveRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ranna_InteractiveServer = grpc.BidiStreamingServer[InteractiveRequest, Event]
+
+func _Ranna_Kill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RannaServer).Kill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ranna_Kill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RannaServer).Kill(ctx, req.(*KillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ranna_GetSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpecRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RannaServer).GetSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ranna_GetSpec_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RannaServer).GetSpec(ctx, req.(*GetSpecRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ranna_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RannaServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ranna_GetInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RannaServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Ranna_ServiceDesc is the grpc.ServiceDesc for Ranna service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Ranna_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ranna.v1.Ranna",
+	HandlerType: (*RannaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Exec",
+			Handler:    _Ranna_Exec_Handler,
+		},
+		{
+			MethodName: "Kill",
+			Handler:    _Ranna_Kill_Handler,
+		},
+		{
+			MethodName: "GetSpec",
+			Handler:    _Ranna_GetSpec_Handler,
+		},
+		{
+			MethodName: "GetInfo",
+			Handler:    _Ranna_GetInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecStream",
+			Handler:       _Ranna_ExecStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Interactive",
+			Handler:       _Ranna_Interactive_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ranna/v1/ranna.proto",
+}
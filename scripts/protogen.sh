@@ -0,0 +1,16 @@
+#!/bin/sh
+
+# Generates the gRPC stubs in pkg/rannapb.
+#
+# https://github.com/protocolbuffers/protobuf
+# https://pkg.go.dev/google.golang.org/protobuf/cmd/protoc-gen-go
+# https://pkg.go.dev/google.golang.org/grpc/cmd/protoc-gen-go-grpc
+
+ROOT="$(dirname "$0")/.."
+MODULE="github.com/ranna-go/ranna/pkg/rannapb"
+
+protoc \
+    -I "$ROOT/proto" \
+    --go_out="$ROOT/pkg/rannapb" --go_opt=module="$MODULE" \
+    --go-grpc_out="$ROOT/pkg/rannapb" --go-grpc_opt=module="$MODULE" \
+    ranna/v1/ranna.proto